@@ -0,0 +1,92 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SetEscapeNonASCII sets whether every non-ASCII character is escaped, as
+// \uXXXX (or a surrogate pair of them, for characters above U+FFFF), so that
+// the output is pure ASCII for legacy parsers. Invalid UTF-8 is escaped as
+// \ufffd.
+//
+// It applies to everything written afterward, including keys, raw values,
+// and sub-builders.
+func (b *Builder) SetEscapeNonASCII(on bool) *Builder {
+	b.s.escapeNonASCII = on
+	return b
+}
+
+// SetEscapeNonASCII sets whether every non-ASCII character is escaped, as
+// \uXXXX (or a surrogate pair of them, for characters above U+FFFF), so that
+// the output is pure ASCII for legacy parsers. Invalid UTF-8 is escaped as
+// \ufffd.
+//
+// It applies to everything written afterward, including keys, raw values,
+// and sub-builders.
+func (b *ListBuilder) SetEscapeNonASCII(on bool) *ListBuilder {
+	b.s.escapeNonASCII = on
+	return b
+}
+
+// outputEscaper holds the state for escaping output as it's written, for
+// SetEscapeNonASCII and SetEscapeForwardSlash.
+type outputEscaper struct {
+	buf []byte
+	// partial is the start of a multi-byte character split across writes.
+	partial []byte
+	// backslash is whether the last byte written was a backslash that escapes
+	// the next one.
+	backslash bool
+}
+
+// writeEscaped writes p with every non-ASCII character and/or forward slash
+// escaped. In valid JSON, both only appear in strings, so this is always safe.
+func (s *stream) writeEscaped(p []byte) (int, error) {
+	n := len(p)
+	e := &s.escaper
+	buf := e.buf[:0]
+	if len(e.partial) > 0 {
+		p = append(e.partial, p...)
+		e.partial = e.partial[:0]
+	}
+	for i := 0; i < len(p); {
+		c := p[i]
+		if c < utf8.RuneSelf || !s.escapeNonASCII {
+			switch {
+			case e.backslash:
+				e.backslash = false
+			case c == '\\':
+				e.backslash = true
+			case c == '/' && s.escapeSlash:
+				buf = append(buf, '\\')
+			}
+			buf = append(buf, c)
+			i++
+			continue
+		}
+		if !utf8.FullRune(p[i:]) {
+			// This might be completed by the next write.
+			e.partial = append(e.partial, p[i:]...)
+			break
+		}
+		r, size := utf8.DecodeRune(p[i:])
+		if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+			buf = appendUnicodeEscape(appendUnicodeEscape(buf, r1), r2)
+		} else {
+			buf = appendUnicodeEscape(buf, r)
+		}
+		i += size
+	}
+	e.buf = buf
+	if _, err := s.write(buf); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func appendUnicodeEscape(buf []byte, r rune) []byte {
+	return append(buf, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+}
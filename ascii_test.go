@@ -0,0 +1,51 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEscapeNonASCII(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(w *bytes.Buffer) error
+	}{
+		{`{"caf\u00e9":"na\u00efve","cjk":"\u65e5\u672c\u8a9e","emoji":"\ud83d\ude00!"}`, func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetEscapeNonASCII(true).Add("café", "naïve")
+			return j.AddString("cjk", "日本語").Add("emoji", "😀!").Close().Err
+		}},
+		{`["\u00e9",{"\u00e9":["\ud83d\ude00"]},"\ufffd"]`, func(w *bytes.Buffer) error {
+			l := NewListBuilder(w).SetEscapeNonASCII(true).AddRaw([]byte(`"é"`))
+			l.AddObjectFunc(func(b *Builder) error {
+				b.AddListFunc("é", func(l *ListBuilder) error {
+					l.AddString("😀")
+					return nil
+				})
+				return nil
+			})
+			return l.AddString("\xff").Close().Err
+		}},
+		// A character split across reads is still escaped as one.
+		{`{"a":"\u00e9\u20ac"}`, func(w *bytes.Buffer) error {
+			r := iotest.OneByteReader(strings.NewReader(`"é€"`))
+			return NewBuilder(w).SetEscapeNonASCII(true).AddReader("a", r).Close().Err
+		}},
+		{`{"a":"é"}`, func(w *bytes.Buffer) error {
+			return NewBuilder(w).SetEscapeNonASCII(false).Add("a", "é").Close().Err
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		if err := test.fn(&buf); err != nil {
+			t.Errorf("%d: Unexpected error <%s>", i, err)
+			continue
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d: have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
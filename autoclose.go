@@ -0,0 +1,21 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// SetAutoCloseSubBuilders sets whether a sub-builder returned by AddObject or
+// AddList that hasn't been closed is closed automatically by the next use of
+// its parent, instead of that setting Err. It applies to the whole document, so
+// a Close of the top-level builder closes everything still open under it.
+func (b *Builder) SetAutoCloseSubBuilders(on bool) *Builder {
+	b.s.autoCloseSubBuilders = on
+	return b
+}
+
+// SetAutoCloseSubBuilders sets whether a sub-builder returned by AddObject or
+// AddList that hasn't been closed is closed automatically by the next use of
+// its parent, instead of that setting Err. It applies to the whole document, so
+// a Close of the top-level builder closes everything still open under it.
+func (b *ListBuilder) SetAutoCloseSubBuilders(on bool) *ListBuilder {
+	b.s.autoCloseSubBuilders = on
+	return b
+}
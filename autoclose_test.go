@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAutoCloseSubBuilders(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(w *bytes.Buffer) error
+	}{
+		{`{"o":{"a":1},"b":2}`, func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetAutoCloseSubBuilders(true)
+			j.AddObject("o").Add("a", 1)
+			return j.Add("b", 2).Close().Err
+		}},
+		{`{"o":{"l":[1,{"x":[]}]}}`, func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetAutoCloseSubBuilders(true)
+			j.AddObject("o").AddList("l").Add(1).AddObject().AddList("x")
+			return j.Close().Err
+		}},
+		{`[[1],{}]`, func(w *bytes.Buffer) error {
+			l := NewListBuilder(w).SetAutoCloseSubBuilders(true)
+			l.AddList().Add(1)
+			l.AddObject()
+			return l.Close().Err
+		}},
+		// A sub-builder closed by hand isn't closed again.
+		{`{"o":{},"b":2}`, func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetAutoCloseSubBuilders(true)
+			j.AddObject("o").Close()
+			return j.Add("b", 2).Close().Err
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		if err := test.fn(&buf); err != nil {
+			t.Errorf("%d: Unexpected error <%s>", i, err)
+			continue
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d: have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	j.AddObject("o")
+	if j.Add("b", 2).Err == nil {
+		t.Error("Expected error by default")
+	}
+}
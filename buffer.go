@@ -0,0 +1,112 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// NewBufferBuilder returns a new encoder that writes to an internal buffer,
+// which is retrieved with String or Bytes once the builder is closed.
+func NewBufferBuilder() *Builder {
+	var buf bytes.Buffer
+	b := NewBuilder(&buf)
+	b.s.owned = &buf
+	return b
+}
+
+// NewBufferListBuilder returns a new encoder that writes to an internal buffer,
+// which is retrieved with String or Bytes once the builder is closed.
+func NewBufferListBuilder() *ListBuilder {
+	var buf bytes.Buffer
+	b := NewListBuilder(&buf)
+	b.s.owned = &buf
+	return b
+}
+
+// String returns the document built by a closed builder from
+// NewBufferBuilder, or "" otherwise. Unlike Bytes, it never sets Err, so
+// printing a builder doesn't change it.
+func (b *Builder) String() string {
+	if b.Err != nil || b.s.checkOwned(b.closed()) != nil {
+		return ""
+	}
+	return b.s.owned.String()
+}
+
+// Bytes returns the document built by a closed builder from NewBufferBuilder.
+// Otherwise, it sets Err (if it isn't already set) and returns nil.
+func (b *Builder) Bytes() []byte {
+	if b.Err == nil {
+		b.Err = b.s.checkOwned(b.closed())
+	}
+	if b.Err != nil {
+		return nil
+	}
+	return b.s.owned.Bytes()
+}
+
+// String returns the document built by a closed builder from
+// NewBufferListBuilder, or "" otherwise. Unlike Bytes, it never sets Err, so
+// printing a builder doesn't change it.
+func (b *ListBuilder) String() string {
+	if b.Err != nil || b.s.checkOwned(b.closed()) != nil {
+		return ""
+	}
+	return b.s.owned.String()
+}
+
+// Bytes returns the document built by a closed builder from
+// NewBufferListBuilder. Otherwise, it sets Err (if it isn't already set) and
+// returns nil.
+func (b *ListBuilder) Bytes() []byte {
+	if b.Err == nil {
+		b.Err = b.s.checkOwned(b.closed())
+	}
+	if b.Err != nil {
+		return nil
+	}
+	return b.s.owned.Bytes()
+}
+
+// WriteTo implements io.WriterTo by writing the document built by a closed
+// builder from NewBufferBuilder to w. It can be called more than once. If the
+// builder isn't closed or doesn't have an internal buffer, it sets Err (if it
+// isn't already set) and returns it.
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	if b.Err == nil {
+		b.Err = b.s.checkOwned(b.closed())
+	}
+	if b.Err != nil {
+		return 0, b.Err
+	}
+	n, err := w.Write(b.s.owned.Bytes())
+	return int64(n), err
+}
+
+// WriteTo implements io.WriterTo by writing the document built by a closed
+// builder from NewBufferListBuilder to w. It can be called more than once. If
+// the builder isn't closed or doesn't have an internal buffer, it sets Err (if
+// it isn't already set) and returns it.
+func (b *ListBuilder) WriteTo(w io.Writer) (int64, error) {
+	if b.Err == nil {
+		b.Err = b.s.checkOwned(b.closed())
+	}
+	if b.Err != nil {
+		return 0, b.Err
+	}
+	n, err := w.Write(b.s.owned.Bytes())
+	return int64(n), err
+}
+
+func (s *stream) checkOwned(closed bool) error {
+	if s.owned == nil {
+		return errors.New("Bytes and WriteTo require a builder with an internal buffer")
+	}
+	if !closed {
+		return errors.New("Bytes or WriteTo called before Close()")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestBufferBuilder(t *testing.T) {
+	j := NewBufferBuilder().Add("a", 1)
+	j.AddListFunc("l", g)
+	if got := j.String(); got != "" || j.Err != nil {
+		t.Errorf("have <%s> <%v> want nothing before Close", got, j.Err)
+	}
+	if got := j.Bytes(); got != nil || j.Err == nil {
+		t.Errorf("have <%s> <%v> want an error before Close", got, j.Err)
+	}
+
+	j = NewBufferBuilder().Add("a", 1).AddListFunc("l", g).Close()
+	if got, want := j.String(), `{"a":1,"l":[1,2,3]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if got, want := string(j.Bytes()), `{"a":1,"l":[1,2,3]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+
+	l := NewBufferListBuilder().Add(1).AddObjectFunc(f).Close()
+	if got, want := l.String(), `[1,{"baz":7}]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	var buf bytes.Buffer
+	j = NewBuilder(&buf).Add("a", 1).Close()
+	if got := fmt.Sprintf("%v", j); got != "" || j.Err != nil {
+		t.Errorf("have <%s> <%v> want printing to leave the builder alone", got, j.Err)
+	}
+	if got := j.Bytes(); got != nil || j.Err == nil {
+		t.Errorf("have <%s> <%v> want an error with an external writer", got, j.Err)
+	}
+	l = NewListBuilder(&buf).Close()
+	if got := l.Bytes(); got != nil || l.Err == nil {
+		t.Errorf("have <%s> <%v> want an error with an external writer", got, l.Err)
+	}
+}
+
+func TestBufferBuilderWriteTo(t *testing.T) {
+	var _ io.WriterTo = NewBufferBuilder()
+
+	j := NewBufferBuilder().Add("a", 1)
+	var buf bytes.Buffer
+	if n, err := j.WriteTo(&buf); n != 0 || err == nil {
+		t.Errorf("have %d <%v> want an error before Close", n, err)
+	}
+
+	const want = `{"a":1,"l":[1,2,3]}`
+	j = NewBufferBuilder().Add("a", 1).AddListFunc("l", g).Close()
+	n, err := j.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(want)) || buf.String() != want {
+		t.Errorf("have %d <%s> want %d <%s>", n, buf.String(), len(want), want)
+	}
+
+	buf.Reset()
+	l := NewBufferListBuilder().Add(1).Close()
+	if n, err := l.WriteTo(&buf); err != nil || n != 3 || buf.String() != `[1]` {
+		t.Errorf("have %d <%s> <%v> want 3 <[1]>", n, buf.String(), err)
+	}
+}
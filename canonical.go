@@ -0,0 +1,182 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// SetCanonical sets whether the document is emitted as canonical JSON, per
+// RFC 8785 (the JSON Canonicalization Scheme), which is suitable for signing
+// and content addressing: object keys are sorted, there's no insignificant
+// whitespace, and numbers and strings have a single representation. A
+// document that can't be canonicalized, like one with a duplicate key or a
+// number out of the range of a float64, sets Err.
+//
+// Sorting keys means no part of the document can be written before all of it
+// is known, so, like WithFinalizer, this buffers the whole document in memory
+// and writes it at Close. It must be called on a top-level builder before
+// anything is added.
+func (b *Builder) SetCanonical(on bool) *Builder {
+	if b.Err != nil {
+		return b
+	}
+	if b.opened {
+		b.Err = errors.New("SetCanonical called after output was written")
+		return b
+	}
+	b.s.canonical = on
+	return b
+}
+
+// canonicalize returns the RFC 8785 canonical form of the JSON document data.
+func canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := canonicalizeValue(&buf, dec); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("Canonical JSON requires a single value")
+	}
+	return buf.Bytes(), nil
+}
+
+func canonicalizeValue(buf *bytes.Buffer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			return canonicalizeArray(buf, dec)
+		}
+		return canonicalizeObject(buf, dec)
+	case string:
+		appendCanonicalString(buf, t)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(t), 64)
+		if err != nil {
+			return fmt.Errorf("Canonical JSON number %s out of range", t)
+		}
+		buf.WriteString(jsNumber(f))
+	case bool:
+		if t {
+			buf.Write(trueBytes)
+		} else {
+			buf.Write(falseBytes)
+		}
+	case nil:
+		buf.Write(nullBytes)
+	}
+	return nil
+}
+
+func canonicalizeArray(buf *bytes.Buffer, dec *json.Decoder) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := canonicalizeValue(buf, dec); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	_, err := dec.Token()
+	return err
+}
+
+type canonicalMember struct {
+	key   string
+	utf16 []uint16
+	value []byte
+}
+
+func canonicalizeObject(buf *bytes.Buffer, dec *json.Decoder) error {
+	var members []canonicalMember
+	seen := make(map[string]struct{})
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("Canonical JSON doesn't allow duplicate key %q", key)
+		}
+		seen[key] = struct{}{}
+		var value bytes.Buffer
+		if err := canonicalizeValue(&value, dec); err != nil {
+			return err
+		}
+		members = append(members, canonicalMember{key, utf16.Encode([]rune(key)), value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	// Keys are sorted by their UTF-16 code units.
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i].utf16, members[j].utf16
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		appendCanonicalString(buf, m.key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// appendCanonicalString writes s as a JSON string, escaping only what RFC 8785
+// requires.
+func appendCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,87 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		// The examples from RFC 8785 sections 3.2.2 and 3.2.3.
+		{
+			`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+				`"string":"` + "€" + `$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			`{"€":"Euro Sign","\r":"Carriage Return","דּ":"Hebrew Letter Dalet With Dagesh",` +
+				`"1":"One","😀":"Emoji: Grinning Face","\u0080":"Control",` +
+				`"ö":"Latin Small Letter O With Diaeresis"}`,
+			`{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control",` +
+				`"` + "ö" + `":"Latin Small Letter O With Diaeresis","` + "€" + `":"Euro Sign",` +
+				`"` + "\U0001f600" + `":"Emoji: Grinning Face","` + "דּ" + `":"Hebrew Letter Dalet With Dagesh"}`,
+		},
+		// Number edge cases from the RFC 8785 appendix.
+		{`[0, -0, 1e21, 1e20, 5e-324, 1.7976931348623157e308, 9007199254740993, 0.000001, 1e-7]`,
+			`[0,0,1e+21,100000000000000000000,5e-324,1.7976931348623157e+308,9007199254740992,0.000001,1e-7]`},
+		{` { "b" : [ { "z" : 1 , "a" : { "y" : 2 , "x" : 3 } } ] , "a" : "\u001f" } `,
+			`{"a":"\u001f","b":[{"a":{"x":3,"y":2},"z":1}]}`},
+	}
+	for i, test := range tests {
+		out, err := canonicalize([]byte(test.in))
+		if err != nil {
+			t.Errorf("%d: Unexpected error <%s>", i, err)
+			continue
+		}
+		if got := string(out); got != test.out {
+			t.Errorf("%d: have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	for i, in := range []string{`{"a":1,"a":2}`, `[1e400]`, `1 2`} {
+		if _, err := canonicalize([]byte(in)); err == nil {
+			t.Errorf("%d: Expected error for <%s>", i, in)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetCanonical(true).SetIndent("", "  ")
+	j.Add("z", 4.50).Add("b", map[string]int{"y": 1, "x": 2})
+	l := j.AddList("a").AddFloat64(1e30)
+	l.AddObject().Add("ö", 1).Add("o", math.Copysign(0, -1)).Close()
+	l.Close()
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":[1e+30,{"o":0,"`+"ö"+`":1}],"b":{"x":2,"y":1},"z":4.5}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).SetCanonical(true).SetCanonical(false).Add("b", 1).Add("a", 2).Close()
+	if got, want := buf.String(), `{"b":1,"a":2}`; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+
+	buf.Reset()
+	if j = NewBuilder(&buf).Add("a", 1).SetCanonical(true); j.Err == nil {
+		t.Error("Expected error")
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).SetCanonical(true).AddRaw("a", []byte(`1`)).AddRaw("a", []byte(`2`)).Close()
+	if j.Err == nil || buf.Len() != 0 {
+		t.Errorf("have <%s> <%v> want an error and no output", buf.String(), j.Err)
+	}
+}
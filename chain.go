@@ -0,0 +1,71 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+)
+
+// A ChainedListBuilder writes a tamper-evident JSON list of objects. Each
+// object gets a leading "prevHash" field holding the hex SHA-256 of the exact
+// bytes of the previous element (null for the first element).
+//
+// Each element is buffered in memory so that it can be hashed.
+type ChainedListBuilder struct {
+	l    *ListBuilder
+	buf  bytes.Buffer
+	prev string
+	Err  error
+}
+
+// NewChainedListBuilder returns a new ChainedListBuilder that writes to w.
+func NewChainedListBuilder(w io.Writer) *ChainedListBuilder {
+	return &ChainedListBuilder{l: NewListBuilder(w)}
+}
+
+// AddObjectFunc emits a JSON object value (computed from f) as the next
+// element, with "prevHash" added before any of the fields from f.
+func (c *ChainedListBuilder) AddObjectFunc(f BuilderFunc) *ChainedListBuilder {
+	if c.Err != nil {
+		return c
+	}
+
+	c.buf.Reset()
+	b := NewBuilder(&c.buf)
+	if c.prev == "" {
+		b.AddNull("prevHash")
+	} else {
+		b.Add("prevHash", c.prev)
+	}
+	if c.Err = f(b); c.Err != nil {
+		return c
+	}
+	if c.Err = b.Close().Err; c.Err != nil {
+		return c
+	}
+
+	if c.l.preadd() == nil {
+		c.l.write(c.buf.Bytes())
+	}
+	c.Err = c.l.Err
+	sum := sha256.Sum256(c.buf.Bytes())
+	c.prev = hex.EncodeToString(sum[:])
+	return c
+}
+
+// Head returns the hex SHA-256 of the last element added, which would be the
+// "prevHash" of the next one.
+func (c *ChainedListBuilder) Head() string {
+	return c.prev
+}
+
+// Close finalizes this JSON list and must be called for it to be complete.
+func (c *ChainedListBuilder) Close() *ChainedListBuilder {
+	if c.Err == nil {
+		c.Err = c.l.Close().Err
+	}
+	return c
+}
@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestChainedListBuilder(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewChainedListBuilder(&buf)
+	for i := 0; i < 3; i++ {
+		c.AddObjectFunc(func(b *Builder) error {
+			b.Add("i", i)
+			return nil
+		})
+	}
+	if c.Close(); c.Err != nil {
+		t.Fatal(c.Err)
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &elements); err != nil {
+		t.Fatal(err)
+	}
+	if len(elements) != 3 {
+		t.Fatalf("have %d elements want 3", len(elements))
+	}
+	var prev *string
+	for i, element := range elements {
+		var e struct {
+			PrevHash *string `json:"prevHash"`
+			I        int     `json:"i"`
+		}
+		if err := json.Unmarshal(element, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.I != i {
+			t.Errorf("%d have i=%d", i, e.I)
+		}
+		if (prev == nil) != (e.PrevHash == nil) || (prev != nil && *prev != *e.PrevHash) {
+			t.Errorf("%d have prevHash %v want %v", i, e.PrevHash, prev)
+		}
+		sum := sha256.Sum256(element)
+		h := hex.EncodeToString(sum[:])
+		prev = &h
+	}
+	if c.Head() != *prev {
+		t.Errorf("have head %s want %s", c.Head(), *prev)
+	}
+}
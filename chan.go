@@ -0,0 +1,29 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// AddFromChan emits each value received from ch as the next element, in order,
+// until ch is closed.
+//
+// It stops early, without draining ch, once Err is set (including when the
+// context of a builder from NewListBuilderContext is done), so the sender must
+// not block forever on a send that may never be received. A done context also
+// stops it while it's waiting on ch.
+func (b *ListBuilder) AddFromChan(ch <-chan interface{}) *ListBuilder {
+	var done <-chan struct{}
+	if b.s.ctx != nil {
+		done = b.s.ctx.Done()
+	}
+	for b.Err == nil {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return b
+			}
+			b.Add(v)
+		case <-done:
+			b.Err = b.s.ctx.Err()
+		}
+	}
+	return b
+}
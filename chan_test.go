@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAddFromChan(t *testing.T) {
+	ch := make(chan interface{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+
+	var buf bytes.Buffer
+	l := NewListBuilder(&buf).Add("start").AddFromChan(ch).Add("end").Close()
+	if l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	if got, want := buf.String(), `["start",0,1,2,3,4,"end"]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// It stops at the first error.
+	ch = make(chan interface{}, 3)
+	ch <- 1
+	ch <- make(chan int)
+	ch <- 3
+	close(ch)
+	buf.Reset()
+	if l := NewListBuilder(&buf).AddFromChan(ch); l.Err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), `[1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if len(ch) != 1 {
+		t.Errorf("have %d values left want 1", len(ch))
+	}
+
+	// A done context stops it while it's waiting on a slow sender.
+	ctx, cancel := context.WithCancel(context.Background())
+	buf.Reset()
+	l = NewListBuilderContext(ctx, &buf).Add(1)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if l.AddFromChan(make(chan interface{})); l.Err != context.Canceled {
+		t.Errorf("have <%v> want <%v>", l.Err, context.Canceled)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// A WholeFloatMode controls how float64 values that are whole numbers are
+// emitted.
+type WholeFloatMode int
+
+const (
+	// WholeFloatDefault emits whole floats like the stdlib: 3 or 1e+21.
+	WholeFloatDefault WholeFloatMode = iota
+	// WholeFloatAsInt emits whole floats as integers: 3 or
+	// 1000000000000000000000.
+	WholeFloatAsInt
+	// WholeFloatAsFloat emits whole floats with a decimal point: 3.0 or 1e+21.
+	WholeFloatAsFloat
+)
+
+// CoercionRules normalize dynamically typed values, like those decoded by the
+// stdlib into an interface{}.
+type CoercionRules struct {
+	// WholeFloats controls how float64 values that are whole numbers are
+	// emitted.
+	WholeFloats WholeFloatMode
+	// Nil, if non-nil, is emitted in place of nil values.
+	Nil interface{}
+}
+
+// WithCoercion applies rules to every value in the document, including those
+// nested in map[string]interface{} and []interface{} values.
+func (b *Builder) WithCoercion(rules CoercionRules) *Builder {
+	b.s.coercion = &rules
+	return b
+}
+
+// WithCoercion applies rules to every value in the document, including those
+// nested in map[string]interface{} and []interface{} values.
+func (b *ListBuilder) WithCoercion(rules CoercionRules) *ListBuilder {
+	b.s.coercion = &rules
+	return b
+}
+
+func (r *CoercionRules) coerce(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return r.Nil
+	case float64:
+		return r.coerceFloat(v)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = r.coerce(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = r.coerce(e)
+		}
+		return l
+	}
+	return v
+}
+
+func (r *CoercionRules) coerceFloat(f float64) interface{} {
+	if math.IsInf(f, 0) || math.Trunc(f) != f {
+		return f
+	}
+	switch r.WholeFloats {
+	case WholeFloatAsInt:
+		return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+	case WholeFloatAsFloat:
+		if math.Abs(f) < 1e21 {
+			return json.Number(strconv.FormatFloat(f, 'f', 1, 64))
+		}
+	}
+	return f
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var coercionTests = []struct {
+	out   string
+	rules CoercionRules
+}{
+	{`{"v":{"big":1e+21,"f":1.5,"i":3,"l":[2,null],"n":null,"s":"x"}}`, CoercionRules{}},
+	{`{"v":{"big":1000000000000000000000,"f":1.5,"i":3,"l":[2,null],"n":null,"s":"x"}}`, CoercionRules{
+		WholeFloats: WholeFloatAsInt,
+	}},
+	{`{"v":{"big":1e+21,"f":1.5,"i":3.0,"l":[2.0,""],"n":"","s":"x"}}`, CoercionRules{
+		WholeFloats: WholeFloatAsFloat,
+		Nil:         "",
+	}},
+}
+
+func TestCoercion(t *testing.T) {
+	var decoded interface{}
+	in := `{"i":3,"f":1.5,"big":1e21,"n":null,"s":"x","l":[2,null]}`
+	if err := json.Unmarshal([]byte(in), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range coercionTests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).WithCoercion(test.rules).Add("v", decoded).Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
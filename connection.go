@@ -0,0 +1,70 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"io"
+)
+
+// A ConnectionBuilder writes a GraphQL Relay-style cursor connection:
+// {"edges":[{"node":{...},"cursor":"..."},...],"pageInfo":{...}}.
+type ConnectionBuilder struct {
+	b      *Builder
+	edges  *ListBuilder
+	cursor [2]*string
+	Err    error
+}
+
+// NewConnectionBuilder returns a new ConnectionBuilder that writes to w.
+func NewConnectionBuilder(w io.Writer) *ConnectionBuilder {
+	return newConnectionBuilder(NewBuilder(w))
+}
+
+// AddConnection returns a builder for a connection object value with the given
+// key.
+//
+// Close() must be called on the connection before using this builder again.
+func (b *Builder) AddConnection(key string) *ConnectionBuilder {
+	return newConnectionBuilder(b.AddObject(key))
+}
+
+func newConnectionBuilder(b *Builder) *ConnectionBuilder {
+	c := &ConnectionBuilder{b: b, edges: b.AddList("edges")}
+	c.Err = c.edges.Err
+	return c
+}
+
+// Edge emits an edge with the given cursor and the node computed from f.
+func (c *ConnectionBuilder) Edge(cursor string, node BuilderFunc) *ConnectionBuilder {
+	if c.Err != nil {
+		return c
+	}
+	c.Err = c.edges.AddObjectFunc(func(b *Builder) error {
+		b.AddObjectFunc("node", node)
+		b.Add("cursor", cursor)
+		return nil
+	}).Err
+
+	if c.cursor[0] == nil {
+		c.cursor[0] = &cursor
+	}
+	c.cursor[1] = &cursor
+	return c
+}
+
+// Close finalizes the connection, emitting its pageInfo. The start and end
+// cursors are those of the first and last edges, or null if there were none.
+func (c *ConnectionBuilder) Close(hasPreviousPage, hasNextPage bool) *ConnectionBuilder {
+	if c.Err != nil {
+		return c
+	}
+	c.edges.Close()
+	c.Err = c.b.AddObjectFunc("pageInfo", func(b *Builder) error {
+		b.Add("hasPreviousPage", hasPreviousPage)
+		b.Add("hasNextPage", hasNextPage)
+		b.Add("startCursor", c.cursor[0])
+		b.Add("endCursor", c.cursor[1])
+		return nil
+	}).Close().Err
+	return c
+}
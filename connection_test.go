@@ -0,0 +1,39 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConnectionBuilder(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewConnectionBuilder(&buf)
+	for _, name := range []string{"a", "b"} {
+		c.Edge("cursor-"+name, func(b *Builder) error {
+			b.Add("name", name)
+			return nil
+		})
+	}
+	if c.Close(false, true); c.Err != nil {
+		t.Fatal(c.Err)
+	}
+	want := `{"edges":[{"node":{"name":"a"},"cursor":"cursor-a"},{"node":{"name":"b"},"cursor":"cursor-b"}],` +
+		`"pageInfo":{"hasPreviousPage":false,"hasNextPage":true,"startCursor":"cursor-a","endCursor":"cursor-b"}}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j := NewBuilder(&buf)
+	if c := j.AddConnection("friends").Close(false, false); c.Err != nil {
+		t.Fatal(c.Err)
+	}
+	j.Close()
+	want = `{"friends":{"edges":[],` +
+		`"pageInfo":{"hasPreviousPage":false,"hasNextPage":false,"startCursor":null,"endCursor":null}}}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"context"
+	"io"
+)
+
+// NewBuilderContext returns a new encoder that writes to w, like NewBuilder,
+// but that stops once ctx is done. Every add (in this builder or any
+// sub-builder) checks ctx first and, if it's done, sets Err to ctx.Err() instead
+// of writing anything.
+func NewBuilderContext(ctx context.Context, w io.Writer) *Builder {
+	b := NewBuilder(w)
+	b.s.ctx = ctx
+	return b
+}
+
+// NewListBuilderContext returns a new encoder that writes to w, like
+// NewListBuilder, but that stops once ctx is done. Every add (in this builder or
+// any sub-builder) checks ctx first and, if it's done, sets Err to ctx.Err()
+// instead of writing anything.
+func NewListBuilderContext(ctx context.Context, w io.Writer) *ListBuilder {
+	b := NewListBuilder(w)
+	b.s.ctx = ctx
+	return b
+}
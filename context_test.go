@@ -0,0 +1,50 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestBuilderContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var buf bytes.Buffer
+	j := NewBuilderContext(ctx, &buf).Add("a", 1)
+	l := j.AddList("l").Add(2)
+	cancel()
+	l.Add(3).AddString("x")
+	if l.Err != context.Canceled {
+		t.Errorf("have <%v> want <%v>", l.Err, context.Canceled)
+	}
+	l.Close()
+	j.Add("b", 4).Close()
+	if j.Err != context.Canceled {
+		t.Errorf("have <%v> want <%v>", j.Err, context.Canceled)
+	}
+	if got, want := buf.String(), `{"a":1,"l":[2`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	buf.Reset()
+	n := 0
+	lb := NewListBuilderContext(ctx, &buf)
+	for i := 0; i < 10; i++ {
+		if i == 3 {
+			cancel()
+		}
+		if lb.Add(i).Err == nil {
+			n++
+		}
+	}
+	if n != 3 || lb.Err != context.Canceled {
+		t.Errorf("have %d adds and <%v> want 3 and <%v>", n, lb.Err, context.Canceled)
+	}
+	if got, want := buf.String(), `[0,1,2`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
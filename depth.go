@@ -0,0 +1,77 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"fmt"
+)
+
+// WithMaxFuncDepth limits how deeply AddObjectFunc and AddListFunc callbacks
+// may nest (across the whole document) to n. A call that would exceed it sets
+// Err instead of running its callback, which turns runaway recursion in a
+// callback into an error instead of a stack overflow.
+func (b *Builder) WithMaxFuncDepth(n int) *Builder {
+	b.s.maxFuncDepth = n
+	return b
+}
+
+// WithMaxFuncDepth limits how deeply AddObjectFunc and AddListFunc callbacks
+// may nest (across the whole document) to n. A call that would exceed it sets
+// Err instead of running its callback, which turns runaway recursion in a
+// callback into an error instead of a stack overflow.
+func (b *ListBuilder) WithMaxFuncDepth(n int) *ListBuilder {
+	b.s.maxFuncDepth = n
+	return b
+}
+
+// SetMaxDepth limits how deeply objects and lists may nest (across the whole
+// document, counting the top-level one) to n. Adding an object or list that
+// would exceed it, with any of AddObject, AddList, AddObjectFunc, or
+// AddListFunc, sets Err instead. Values marshaled by the stdlib aren't counted.
+func (b *Builder) SetMaxDepth(n int) *Builder {
+	b.s.maxDepth = n
+	return b
+}
+
+// SetMaxDepth limits how deeply objects and lists may nest (across the whole
+// document, counting the top-level one) to n. Adding an object or list that
+// would exceed it, with any of AddObject, AddList, AddObjectFunc, or
+// AddListFunc, sets Err instead. Values marshaled by the stdlib aren't counted.
+func (b *ListBuilder) SetMaxDepth(n int) *ListBuilder {
+	b.s.maxDepth = n
+	return b
+}
+
+// descend is called before adding a nested object or list and returns an error
+// if that isn't allowed.
+func (b *Builder) descend() error {
+	if b.done() {
+		return b.Err
+	}
+	b.open()
+	if b.Err == nil {
+		b.Err = b.s.checkDepth()
+	}
+	return b.Err
+}
+
+// descend is called before adding a nested object or list and returns an error
+// if that isn't allowed.
+func (b *ListBuilder) descend() error {
+	if b.done() {
+		return b.Err
+	}
+	b.open()
+	if b.Err == nil {
+		b.Err = b.s.checkDepth()
+	}
+	return b.Err
+}
+
+// checkDepth returns an error if opening a builder would exceed the max depth.
+func (s *stream) checkDepth() error {
+	if s.maxDepth > 0 && s.depth >= s.maxDepth {
+		return fmt.Errorf("Max depth of %d exceeded", s.maxDepth)
+	}
+	return nil
+}
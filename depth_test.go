@@ -0,0 +1,95 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMaxFuncDepth(t *testing.T) {
+	var calls int
+	var recurse BuilderFunc
+	recurse = func(b *Builder) error {
+		calls++
+		b.AddObjectFunc("child", recurse)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithMaxFuncDepth(100).AddObjectFunc("root", recurse)
+	if j.Err == nil {
+		t.Fatal("Expected error")
+	}
+	if calls != 100 {
+		t.Errorf("have %d calls want 100", calls)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).WithMaxFuncDepth(3).AddObjectFunc("foo", h).Close()
+	if j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+	if got, want := buf.String(), `{"foo":{"corge":{"baz":7},"grault":{"garply":[1,2,3]}}}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	// nest alternates objects and lists, levels deep in total.
+	var nest func(levels int) BuilderFunc
+	var nestList func(levels int) ListBuilderFunc
+	nest = func(levels int) BuilderFunc {
+		return func(b *Builder) error {
+			if levels > 1 {
+				b.AddListFunc("l", nestList(levels-1))
+			}
+			return nil
+		}
+	}
+	nestList = func(levels int) ListBuilderFunc {
+		return func(b *ListBuilder) error {
+			if levels > 1 {
+				b.AddObjectFunc(nest(levels - 1))
+			}
+			return nil
+		}
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetMaxDepth(4)
+	if err := nest(4)(j); err != nil {
+		t.Fatal(err)
+	}
+	if j.Close(); j.Err != nil {
+		t.Fatalf("Unexpected error <%s>", j.Err)
+	}
+	if got, want := buf.String(), `{"l":[{"l":[]}]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).SetMaxDepth(4)
+	if err := nest(5)(j); err != nil {
+		t.Fatal(err)
+	}
+	if j.Close(); j.Err == nil || j.Err.Error() != "Max depth of 4 exceeded" {
+		t.Errorf("have <%v> want a max depth error", j.Err)
+	}
+
+	// The non-Func variants are limited too.
+	buf.Reset()
+	l := NewListBuilder(&buf).SetMaxDepth(2)
+	o := l.AddObject()
+	if o.AddObject("o").Err == nil || o.AddList("l").Err == nil {
+		t.Error("Expected error")
+	}
+	buf.Reset()
+	l = NewListBuilder(&buf).SetMaxDepth(1)
+	if l.AddList().Err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), `[`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// WithDiscardOnFuncError changes what happens when the callback passed to an
+// AddObjectFunc or AddListFunc (anywhere in the document) returns an error.
+// Instead of storing the error in Err and emitting whatever the callback had
+// built, the pair (or list element) is omitted entirely and the error is
+// dropped, so the rest of the document can still be built. Errors that don't
+// come from the callback itself, like a value that fails to marshal or one from
+// the output, are still stored in Err.
+//
+// This requires buffering each callback's output in memory until it returns,
+// which gives up streaming within those values.
+func (b *Builder) WithDiscardOnFuncError() *Builder {
+	b.s.discardOnFuncError = true
+	return b
+}
+
+// WithDiscardOnFuncError changes what happens when the callback passed to an
+// AddObjectFunc or AddListFunc (anywhere in the document) returns an error.
+// Instead of storing the error in Err and emitting whatever the callback had
+// built, the list element (or pair) is omitted entirely and the error is
+// dropped, so the rest of the document can still be built. Errors that don't
+// come from the callback itself, like a value that fails to marshal or one from
+// the output, are still stored in Err.
+//
+// This requires buffering each callback's output in memory until it returns,
+// which gives up streaming within those values.
+func (b *ListBuilder) WithDiscardOnFuncError() *ListBuilder {
+	b.s.discardOnFuncError = true
+	return b
+}
+
+// addFuncOrDiscard adds the pair with the value emitted by fn, unless fn's
+// callback returns an error, in which case nothing is added.
+func (b *Builder) addFuncOrDiscard(key string, fn func() (fnErr, err error)) *Builder {
+	if b.done() {
+		return b
+	}
+	b.open()
+	undo := b.undoAdd(key)
+	var fnErr, err error
+	raw := b.s.capture(func() {
+		if b.preadd(key) == nil {
+			fnErr, err = fn()
+		}
+	})
+	if b.Err != nil {
+		return b
+	}
+	if err != nil {
+		// Only the callback's own error is dropped, never one from the
+		// output or a value.
+		b.Err = err
+		return b
+	}
+	if fnErr != nil {
+		undo()
+		return b
+	}
+	b.write(raw)
+	return b
+}
+
+// addFuncOrDiscard adds the element emitted by fn, unless fn's callback returns
+// an error, in which case nothing is added.
+func (b *ListBuilder) addFuncOrDiscard(fn func() (fnErr, err error)) *ListBuilder {
+	if b.done() {
+		return b
+	}
+	b.open()
+	undo := b.undoAdd()
+	var fnErr, err error
+	raw := b.s.capture(func() {
+		if b.preadd() == nil {
+			fnErr, err = fn()
+		}
+	})
+	if b.Err != nil {
+		return b
+	}
+	if err != nil {
+		// Only the callback's own error is dropped, never one from the
+		// output or a value.
+		b.Err = err
+		return b
+	}
+	if fnErr != nil {
+		undo()
+		return b
+	}
+	b.write(raw)
+	b.endElement()
+	return b
+}
+
+// capture runs fn with the stream's output redirected to a buffer and returns
+// what was written.
+func (s *stream) capture(fn func()) []byte {
+	var buf bytes.Buffer
+	w, observer, written := s.w, s.observer, s.stats.BytesWritten
+	s.w, s.observer = &buf, nil
+	defer func() { s.w, s.observer, s.stats.BytesWritten = w, observer, written }()
+	fn()
+	return buf.Bytes()
+}
+
+// undoAdd returns a func that reverts b, and the document's Stats, to how they
+// are now, for backing out of a pair with key that's about to be added.
+func (b *Builder) undoAdd(key string) func() {
+	state, n, stats := b.state, b.n, b.s.stats
+	key = b.s.mapKey(key)
+	var seen bool
+	if b.req != nil {
+		seen = b.req.seen[key]
+	}
+	return func() {
+		b.state, b.n, b.s.stats = state, n, stats
+		delete(b.keys, key)
+		if b.req != nil && !seen {
+			delete(b.req.seen, key)
+		}
+	}
+}
+
+// undoAdd returns a func that reverts b, and the document's Stats, to how they
+// are now, for backing out of an element that's about to be added.
+func (b *ListBuilder) undoAdd() func() {
+	state, n, stats := b.state, b.n, b.s.stats
+	return func() {
+		b.state, b.n, b.s.stats = state, n, stats
+	}
+}
+
+// SetBufferSubBuilders sets whether each sub-builder returned by AddObject or
+// AddList (anywhere in the document) holds its output, including its key or
+// separator, in memory until it's closed, so that it can be backed out of with
+// Discard.
+//
+// This gives up streaming within those values: nothing in a sub-builder, or in
+// the sub-builders nested in it, is written until it's closed.
+func (b *Builder) SetBufferSubBuilders(on bool) *Builder {
+	b.s.bufferSubBuilders = on
+	return b
+}
+
+// SetBufferSubBuilders sets whether each sub-builder returned by AddObject or
+// AddList (anywhere in the document) holds its output, including its key or
+// separator, in memory until it's closed, so that it can be backed out of with
+// Discard.
+//
+// This gives up streaming within those values: nothing in a sub-builder, or in
+// the sub-builders nested in it, is written until it's closed.
+func (b *ListBuilder) SetBufferSubBuilders(on bool) *ListBuilder {
+	b.s.bufferSubBuilders = on
+	return b
+}
+
+var errDiscardUnbuffered = errors.New(
+	"Discard requires a sub-builder from AddObject or AddList with SetBufferSubBuilders")
+
+// Discard backs out of this sub-builder, as if the AddObject or AddList call
+// that returned it had never been made: its key (or separator) and everything
+// added to it are dropped, and its parent can be used again as though it had
+// been closed. It requires SetBufferSubBuilders, otherwise Err is set.
+func (b *Builder) Discard() {
+	if b.done() {
+		return
+	}
+	if b.pending == nil {
+		b.Err = errDiscardUnbuffered
+		return
+	}
+	b.s.endPending(b.pending, false)
+	b.pending = nil
+	b.state = closedState
+}
+
+// Discard backs out of this sub-builder, as if the AddObject or AddList call
+// that returned it had never been made: its key (or separator) and everything
+// added to it are dropped, and its parent can be used again as though it had
+// been closed. It requires SetBufferSubBuilders, otherwise Err is set.
+func (b *ListBuilder) Discard() {
+	if b.done() {
+		return
+	}
+	if b.pending == nil {
+		b.Err = errDiscardUnbuffered
+		return
+	}
+	b.s.endPending(b.pending, false)
+	b.pending = nil
+	b.state = closedState
+}
+
+// preaddSub is preadd for a pair whose value is a sub-builder, which, with
+// SetBufferSubBuilders, starts buffering the output.
+func (b *Builder) preaddSub(key string) (*pendingSub, error) {
+	if !b.s.bufferSubBuilders {
+		return nil, b.preadd(key)
+	}
+	p := b.s.beginPending(b.undoAdd(key))
+	if err := b.preadd(key); err != nil {
+		b.s.endPending(p, true)
+		return nil, err
+	}
+	return p, nil
+}
+
+// preaddSub is preadd for an element that's a sub-builder, which, with
+// SetBufferSubBuilders, starts buffering the output.
+func (b *ListBuilder) preaddSub() (*pendingSub, error) {
+	if !b.s.bufferSubBuilders {
+		return nil, b.preadd()
+	}
+	p := b.s.beginPending(b.undoAdd())
+	if err := b.preadd(); err != nil {
+		b.s.endPending(p, true)
+		return nil, err
+	}
+	return p, nil
+}
+
+// pendingSub is the buffered output of a sub-builder and what's needed to
+// either write it or back out of it.
+type pendingSub struct {
+	buf      bytes.Buffer
+	w        io.Writer
+	observer func([]byte)
+	written  int64
+	depth    int
+	undo     func()
+}
+
+// beginPending redirects the stream's output to a buffer. undo is run if it's
+// discarded.
+func (s *stream) beginPending(undo func()) *pendingSub {
+	p := &pendingSub{w: s.w, observer: s.observer, written: s.stats.BytesWritten, depth: s.depth, undo: undo}
+	s.w, s.observer = &p.buf, nil
+	return p
+}
+
+// endPending undoes beginPending and then either writes the buffered output or,
+// if keep is false, drops it and backs out of the sub-builder.
+func (s *stream) endPending(p *pendingSub, keep bool) error {
+	s.w, s.observer, s.stats.BytesWritten = p.w, p.observer, p.written
+	if !keep {
+		s.depth = p.depth
+		p.undo()
+		return nil
+	}
+	_, err := s.Write(p.buf.Bytes())
+	return err
+}
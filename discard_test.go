@@ -0,0 +1,187 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDiscardOnFuncError(t *testing.T) {
+	fail := func(b *Builder) error {
+		b.Add("partial", 1)
+		return errors.New("callback failed")
+	}
+	failList := func(b *ListBuilder) error {
+		b.Add(1)
+		return errors.New("callback failed")
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithDiscardOnFuncError()
+	j.AddObjectFunc("bad", fail).Add("a", 1).AddListFunc("badList", failList).AddObjectFunc("good", f)
+	j.AddObjectFunc("nested", func(b *Builder) error {
+		b.AddObjectFunc("bad", fail)
+		return nil
+	})
+	j.AddListFunc("list", func(b *ListBuilder) error {
+		b.AddObjectFunc(fail).Add(2).AddListFunc(failList).AddListFunc(g)
+		return nil
+	})
+	j.Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"a":1,"good":{"baz":7},"nested":{},"list":[2,[1,2,3]]}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).AddObjectFunc("bad", fail)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+
+	// A discarded pair doesn't count toward required keys or Stats.
+	buf.Reset()
+	j = NewBuilder(&buf).WithDiscardOnFuncError().WithRequiredKeys([]string{"bad"})
+	j.Add("a", 1).AddObjectFunc("bad", fail)
+	if got, want := j.Stats(), (Stats{Adds: 1, BytesWritten: 6, MaxDepth: 1}); got != want {
+		t.Errorf("have %+v want %+v", got, want)
+	}
+	if j.Close(); j.Err == nil {
+		t.Errorf("Expected missing required key error <%s>", buf.String())
+	}
+
+	// Only the callback's own error is dropped.
+	streamErrs := []struct {
+		name string
+		fn   func(w *bytes.Buffer) *Builder
+	}{
+		{"max bytes", func(w *bytes.Buffer) *Builder {
+			return NewBuilder(w).WithDiscardOnFuncError().SetMaxBytes(20).Add("a", 1).
+				AddObjectFunc("o", func(b *Builder) error {
+					b.Add("long", "0123456789")
+					return b.Err
+				})
+		}},
+		{"cancelled", func(w *bytes.Buffer) *Builder {
+			ctx, cancel := context.WithCancel(context.Background())
+			return NewBuilderContext(ctx, w).WithDiscardOnFuncError().
+				AddObjectFunc("o", func(b *Builder) error {
+					cancel()
+					b.Add("a", 1)
+					return nil
+				})
+		}},
+		{"marshal", func(w *bytes.Buffer) *Builder {
+			return NewBuilder(w).WithDiscardOnFuncError().
+				AddObjectFunc("o", func(b *Builder) error {
+					b.Add("c", make(chan int))
+					return nil
+				})
+		}},
+	}
+	for _, test := range streamErrs {
+		buf.Reset()
+		if j := test.fn(&buf).Close(); j.Err == nil {
+			t.Errorf("%s: expected error, got <%s>", test.name, buf.String())
+		}
+	}
+
+	// A panicking callback doesn't leave the output redirected.
+	buf.Reset()
+	j = NewBuilder(&buf).WithDiscardOnFuncError()
+	func() {
+		defer func() { recover() }()
+		j.AddObjectFunc("panic", func(*Builder) error { panic("callback panicked") })
+	}()
+	if j.s.w != &buf {
+		t.Error("Output still redirected after a panic")
+	}
+}
+
+func TestDiscardSubBuilder(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(w *bytes.Buffer) error
+	}{
+		{`{"a":1,"c":3}`, func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetBufferSubBuilders(true).Add("a", 1)
+			o := j.AddObject("b").Add("x", 1)
+			o.AddList("l").Add(1)
+			o.Discard()
+			return j.Add("c", 3).Close().Err
+		}},
+		{`{"b":{"x":1}}`, func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetBufferSubBuilders(true).SetDetectDuplicateKeys(true)
+			j.AddList("b").Add(1).Discard()
+			j.AddObject("b").Add("x", 1).Close()
+			return j.Close().Err
+		}},
+		{`[1,[2],{}]`, func(w *bytes.Buffer) error {
+			l := NewListBuilder(w).SetBufferSubBuilders(true).Add(1)
+			l.AddObject().Add("x", 1).Discard()
+			l.AddList().Add(2).Close()
+			l.AddList().Discard()
+			l.AddObject().Close()
+			return l.Close().Err
+		}},
+		{`[]`, func(w *bytes.Buffer) error {
+			l := NewListBuilder(w).SetBufferSubBuilders(true)
+			l.AddObject().Discard()
+			return l.Close().Err
+		}},
+		{"{\n  \"a\": 1\n}", func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetBufferSubBuilders(true).SetIndent("", "  ").Add("a", 1)
+			j.AddObject("b").Add("x", 1).Discard()
+			return j.Close().Err
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		if err := test.fn(&buf); err != nil {
+			t.Errorf("%d: Unexpected error <%s>", i, err)
+			continue
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d: have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	// Nothing in a buffered sub-builder is written until it's closed.
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetBufferSubBuilders(true).Add("a", 1)
+	o := j.AddObject("b").Add("x", 1)
+	if got, want := buf.String(), `{"a":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	o.Close()
+	if got, want := buf.String(), `{"a":1,"b":{"x":1}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if j.Close(); j.BytesWritten() != int64(buf.Len()) {
+		t.Errorf("have %d bytes written want %d", j.BytesWritten(), buf.Len())
+	}
+
+	// A discarded sub-builder doesn't count toward required keys or Stats.
+	buf.Reset()
+	j = NewBuilder(&buf).SetBufferSubBuilders(true).WithRequiredKeys([]string{"b"})
+	j.Add("a", 1).AddObject("b").Add("x", 1).Discard()
+	if got, want := j.Stats(), (Stats{Adds: 1, BytesWritten: 6, MaxDepth: 1}); got != want {
+		t.Errorf("have %+v want %+v", got, want)
+	}
+	if j.Close(); j.Err == nil {
+		t.Errorf("Expected missing required key error <%s>", buf.String())
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf)
+	j.AddObject("b").Discard()
+	if j.Add("c", 1).Err == nil {
+		t.Error("Expected error without SetBufferSubBuilders")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"io"
+)
+
+// An Encoder writes the JSON encoding of v to w. It's used to encode the values
+// passed to Add (and the other methods that take an interface{}), but not keys
+// or values from typed methods like AddString.
+type Encoder interface {
+	Encode(w io.Writer, v interface{}) error
+}
+
+// SetEncoder replaces the stdlib encoding of values with e for the whole
+// document, including sub-builders. The settings that substitute values before
+// they're encoded, like WithTypeRegistry, still apply.
+//
+// Each value is encoded into a buffer before being written, so that a value
+// that fails to encode writes nothing. A trailing newline, like the one
+// json.Encoder.Encode adds, is trimmed.
+func (b *Builder) SetEncoder(e Encoder) *Builder {
+	b.s.custom = e
+	return b
+}
+
+// SetEncoder replaces the stdlib encoding of values with e for the whole
+// document, including sub-builders. The settings that substitute values before
+// they're encoded, like WithTypeRegistry, still apply.
+//
+// Each value is encoded into a buffer before being written, so that a value
+// that fails to encode writes nothing. A trailing newline, like the one
+// json.Encoder.Encode adds, is trimmed.
+func (b *ListBuilder) SetEncoder(e Encoder) *ListBuilder {
+	b.s.custom = e
+	return b
+}
+
+func (s *stream) encodeCustom(v interface{}) (interface{}, error) {
+	var buf bytes.Buffer
+	if err := s.custom.Encode(&buf, v); err != nil {
+		return nil, err
+	}
+	raw := buf.Bytes()
+	if n := len(raw); n > 0 && raw[n-1] == '\n' {
+		raw = raw[:n-1]
+	}
+	return verbatim(raw), nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// upperEncoder uppercases string values and counts its calls.
+type upperEncoder struct {
+	calls int
+}
+
+func (e *upperEncoder) Encode(w io.Writer, v interface{}) error {
+	e.calls++
+	if s, ok := v.(string); ok {
+		v = strings.ToUpper(s)
+	}
+	if _, ok := v.(chan int); ok {
+		return errors.New("no channels")
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+func TestSetEncoder(t *testing.T) {
+	var e upperEncoder
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetEncoder(&e).Add("a", "foo").Add("b", 1)
+	j.AddObject("o").Add("c", "bar").Close()
+	j.AddList("l").Add("baz").AddString("typed").Close()
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":"FOO","b":1,"o":{"c":"BAR"},"l":["BAZ","typed"]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if e.calls != 4 {
+		t.Errorf("have %d calls want 4", e.calls)
+	}
+
+	buf.Reset()
+	l := NewListBuilder(&buf).SetEncoder(&e).Add("x").Add(make(chan int))
+	if l.Err == nil || l.Err.Error() != "no channels" {
+		t.Errorf("have <%v> want <no channels>", l.Err)
+	}
+	if got, want := buf.String(), `["X"`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// SetSkipNilErrors sets whether AddError with a nil error adds nothing,
+// instead of null.
+func (b *Builder) SetSkipNilErrors(on bool) *Builder {
+	b.s.skipNilErrors = on
+	return b
+}
+
+// SetSkipNilErrors sets whether AddError with a nil error adds nothing,
+// instead of null.
+func (b *ListBuilder) SetSkipNilErrors(on bool) *ListBuilder {
+	b.s.skipNilErrors = on
+	return b
+}
+
+// AddError emits err's message as a string, or null if err is nil (see
+// SetSkipNilErrors). An err that implements json.Marshaler is emitted as what
+// its MarshalJSON returns.
+//
+// If err has structured details, it is instead emitted as an object with the
+// message under "message". An err with a `StatusCode() int` method gets a
+// "status" field, and one with a `Fields() map[string]interface{}` method has
+// those fields added in sorted key order (skipping "message" and "status").
+func (b *Builder) AddError(key string, err error) *Builder {
+	if isNil(err) {
+		if b.s.skipNilErrors {
+			return b
+		}
+		return b.AddNull(key)
+	}
+	v, f := errorValue(err)
+	if f != nil {
+		return b.AddObjectFunc(key, f)
+	}
+	return b.Add(key, v)
+}
+
+// AddError emits err as the next element, like Builder.AddError.
+func (b *ListBuilder) AddError(err error) *ListBuilder {
+	if isNil(err) {
+		if b.s.skipNilErrors {
+			return b
+		}
+		return b.AddNull()
+	}
+	v, f := errorValue(err)
+	if f != nil {
+		return b.AddObjectFunc(f)
+	}
+	return b.Add(v)
+}
+
+// errorValue returns what a non-nil err is emitted as: either a value, or,
+// when it has structured details, a func that fills in an object.
+func errorValue(err error) (interface{}, BuilderFunc) {
+	if _, ok := err.(json.Marshaler); ok {
+		return err, nil
+	}
+
+	fielder, hasFields := err.(interface {
+		Fields() map[string]interface{}
+	})
+	statuser, hasStatus := err.(interface {
+		StatusCode() int
+	})
+	if !hasFields && !hasStatus {
+		return err.Error(), nil
+	}
+
+	return nil, func(b *Builder) error {
+		b.Add("message", err.Error())
+		if hasStatus {
+			b.Add("status", statuser.StatusCode())
+		}
+		if hasFields {
+			fields := fielder.Fields()
+			keys := make([]string, 0, len(fields))
+			for k := range fields {
+				if k != "message" && k != "status" {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				b.Add(k, fields[k])
+			}
+		}
+		return nil
+	}
+}
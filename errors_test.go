@@ -0,0 +1,81 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type fieldsError struct{}
+
+func (fieldsError) Error() string { return "bad input" }
+func (fieldsError) Fields() map[string]interface{} {
+	return map[string]interface{}{"field": "name", "attempt": 3, "message": "ignored"}
+}
+
+type statusError struct{}
+
+func (statusError) Error() string   { return "not found" }
+func (statusError) StatusCode() int { return 404 }
+
+type jsonError struct{ code int }
+
+func (e jsonError) Error() string { return "json error" }
+func (e jsonError) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"code":%d}`, e.code)), nil
+}
+
+var addErrorTests = []struct {
+	out string
+	err error
+}{
+	{`{"error":"boom"}`, errors.New("boom")},
+	{`{"error":null}`, nil},
+	{`{"error":{"message":"bad input","attempt":3,"field":"name"}}`, fieldsError{}},
+	{`{"error":{"message":"not found","status":404}}`, statusError{}},
+	{`{"error":{"code":7}}`, jsonError{7}},
+	{`{"error":{"code":8}}`, &jsonError{8}},
+	{`{"error":null}`, (*statusError)(nil)},
+}
+
+func TestAddError(t *testing.T) {
+	for i, test := range addErrorTests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).AddError("error", test.err).Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+
+		buf.Reset()
+		l := NewListBuilder(&buf).AddError(test.err).Close()
+		want := "[" + test.out[len(`{"error":`):len(test.out)-1] + "]"
+		if got := buf.String(); got != want || l.Err != nil {
+			t.Errorf("%d have <%s> <%v> want <%s>", i, got, l.Err, want)
+		}
+	}
+}
+
+func TestSkipNilErrors(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetSkipNilErrors(true).Add("a", 1).AddError("error", nil)
+	j.AddObjectFunc("o", func(b *Builder) error {
+		b.AddError("error", nil).AddError("cause", errors.New("boom"))
+		return nil
+	}).Close()
+	if got, want := buf.String(), `{"a":1,"o":{"cause":"boom"}}`; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+
+	buf.Reset()
+	var typedNil *statusError
+	l := NewListBuilder(&buf).SetSkipNilErrors(true).AddError(nil).AddError(typedNil)
+	if l.AddError(errors.New("boom")).Close(); buf.String() != `["boom"]` || l.Err != nil {
+		t.Errorf("have <%s> <%v> want <[\"boom\"]>", buf.String(), l.Err)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+)
+
+// WithFinalizer makes the builder buffer the whole document in memory instead
+// of streaming it. At Close, f is called with the complete document and what
+// it returns is written to the underlying writer instead.
+//
+// This gives up streaming entirely, so it's only suitable for documents that
+// comfortably fit in memory. It must be called on a top-level builder before
+// anything is added.
+func (b *Builder) WithFinalizer(f func([]byte) ([]byte, error)) *Builder {
+	if b.Err != nil {
+		return b
+	}
+	if b.opened {
+		b.Err = errors.New("WithFinalizer called after output was written")
+		return b
+	}
+
+	// Buffered bytes aren't output, so they're kept out of the observer and
+	// stats, and the finalized document is what counts against SetMaxBytes.
+	var buf bytes.Buffer
+	dest, observer, written := b.s.w, b.s.observer, b.s.stats.BytesWritten
+	b.s.w, b.s.observer = &buf, nil
+	// The prefix wraps the finalized document rather than being part of it.
+	prefix := b.prefix
+	b.prefix = nil
+	next := b.afterClose
+	b.afterClose = func() error {
+		b.s.w, b.s.observer, b.s.stats.BytesWritten = dest, observer, written
+		out, err := f(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if prefix != nil {
+			if _, err := b.s.Write(prefix); err != nil {
+				return err
+			}
+		}
+		if _, err := b.s.write(out); err != nil {
+			return err
+		}
+		if next != nil {
+			return next()
+		}
+		return nil
+	}
+	return b
+}
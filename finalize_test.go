@@ -0,0 +1,64 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestFinalizer(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithFinalizer(func(doc []byte) ([]byte, error) {
+		if buf.Len() != 0 {
+			t.Error("output was written before the finalizer ran")
+		}
+		return bytes.Replace(doc, []byte("__name__"), bytes.ToUpper([]byte("__name__")), -1), nil
+	})
+	j.Add("greeting", "hi __name__").AddObjectFunc("quz", f).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"greeting":"hi __NAME__","quz":{"baz":7}}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).WithFinalizer(func(doc []byte) ([]byte, error) {
+		return bytes.Replace(doc, []byte(`"__COUNT__"`), []byte("2"), -1), nil
+	})
+	j.Add("a", 1).Add("b", 2).Add("count", "__COUNT__").Close()
+	if got, want := buf.String(), `{"a":1,"b":2,"count":2}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).WithFinalizer(func(doc []byte) ([]byte, error) {
+		return nil, errors.New("finalizer failed")
+	})
+	if j.Add("a", 1).Close(); j.Err == nil {
+		t.Error("Expected error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> want nothing written", buf.String())
+	}
+
+	// The finalized document is what's limited, observed, and counted.
+	buf.Reset()
+	double := func(doc []byte) ([]byte, error) { return append(doc, doc...), nil }
+	j = NewBuilder(&buf).SetMaxBytes(10)
+	if j.WithFinalizer(double).Add("a", 1).Close(); j.Err != ErrMaxBytesExceeded || buf.Len() != 0 {
+		t.Errorf("have <%s> <%v> want <%v>", buf.String(), j.Err, ErrMaxBytesExceeded)
+	}
+	var observed bytes.Buffer
+	j = NewBuilder(&buf).WithWriteObserver(func(p []byte) { observed.Write(p) })
+	j.WithFinalizer(double).Add("a", 1).Close()
+	if got, want := observed.String(), `{"a":1}{"a":1}`; got != want || j.BytesWritten() != int64(len(want)) {
+		t.Errorf("have <%s> %d want <%s> %d", got, j.BytesWritten(), want, len(want))
+	}
+
+	if j := NewBuilder(&buf).Add("a", 1).WithFinalizer(nil); j.Err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Flatten returns a BuilderFunc that emits v as a flat object, with a pair for
+// each leaf value keyed by its RFC 6901 JSON Pointer, like {"/a/b":1,"/c/0":2}.
+//
+// v is first encoded as usual, so anything that can be added as a value can be
+// flattened. Leaves are scalars and empty objects or lists. Pairs are emitted
+// in sorted key order within each object and in index order within each list.
+func Flatten(v interface{}) BuilderFunc {
+	return func(b *Builder) error {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.UseNumber()
+		var decoded interface{}
+		if err := d.Decode(&decoded); err != nil {
+			return err
+		}
+		flatten(b, "", decoded)
+		return nil
+	}
+}
+
+// WriteFlattened writes v to w as a flat object. See Flatten.
+func WriteFlattened(w io.Writer, v interface{}) error {
+	return ObjectWriterFunc(Flatten(v))(w)
+}
+
+func flatten(b *Builder, pointer string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			b.Add(pointer, v)
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flatten(b, pointer+"/"+pointerEscaper.Replace(k), v[k])
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			b.Add(pointer, v)
+			return
+		}
+		for i, e := range v {
+			flatten(b, pointer+"/"+strconv.Itoa(i), e)
+		}
+	default:
+		b.Add(pointer, v)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+var flattenTests = []struct {
+	out string
+	v   interface{}
+}{
+	{`{"/a/b":1,"/a/c":2}`, map[string]interface{}{"a": map[string]int{"c": 2, "b": 1}}},
+	{`{"/0":"x","/1/0":true,"/1/1":null,"/2/k":1.5}`, []interface{}{"x", []interface{}{true, nil}, map[string]float64{"k": 1.5}}},
+	{`{"/a~1b/c~0d":12345678901234567890,"/e":{},"/f":[]}`, map[string]interface{}{
+		"a/b": map[string]uint64{"c~d": 12345678901234567890},
+		"e":   struct{}{},
+		"f":   []int{},
+	}},
+	{`{"":"scalar"}`, "scalar"},
+}
+
+func TestFlatten(t *testing.T) {
+	for i, test := range flattenTests {
+		var buf bytes.Buffer
+		if err := WriteFlattened(&buf, test.v); err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// Flush flushes the underlying writer, if it can be flushed (like a
+// bufio.Writer or an http.ResponseWriter), so that everything written so far
+// is sent on. Otherwise, it's a no-op. An error flushing is stored in Err.
+//
+// It may be called on any builder in the document, at any time.
+func (b *Builder) Flush() error {
+	if b.Err == nil {
+		b.Err = b.s.flush()
+	}
+	return b.Err
+}
+
+// Flush flushes the underlying writer, if it can be flushed (like a
+// bufio.Writer or an http.ResponseWriter), so that everything written so far
+// is sent on. Otherwise, it's a no-op. An error flushing is stored in Err.
+//
+// It may be called on any builder in the document, at any time.
+func (b *ListBuilder) Flush() error {
+	if b.Err == nil {
+		b.Err = b.s.flush()
+	}
+	return b.Err
+}
+
+// SetFlushEvery makes this list call Flush after every n elements, so that a
+// large list is sent on in chunks. Each flush happens as soon as the n-th
+// element is complete: once it's added or, for a sub-builder from AddObject or
+// AddList, once that's closed. Zero, the default, disables it.
+func (b *ListBuilder) SetFlushEvery(n int) *ListBuilder {
+	b.flushEvery = n
+	return b
+}
+
+// endElement is called once the element most recently added to b is complete,
+// to flush it with SetFlushEvery.
+func (b *ListBuilder) endElement() error {
+	if b.flushEvery > 0 && b.Err == nil && b.n%b.flushEvery == 0 {
+		b.Err = b.s.flush()
+	}
+	return b.Err
+}
+
+func (s *stream) flush() error {
+	if s.sinks != nil {
+		for i, w := range s.sinks.secondary {
+			if s.sinks.errs[i] == nil {
+				s.sinks.errs[i] = flush(w)
+			}
+		}
+	}
+	return flush(s.w)
+}
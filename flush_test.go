@@ -0,0 +1,110 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// errFlushRecorder is like flushRecorder, but with the Flush signature that
+// returns an error.
+type errFlushRecorder struct {
+	bytes.Buffer
+	flushes int
+	err     error
+}
+
+func (w *errFlushRecorder) Flush() error {
+	w.flushes++
+	return w.err
+}
+
+func TestFlush(t *testing.T) {
+	var w errFlushRecorder
+	l := NewListBuilder(&w).Add(1)
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	o := l.AddObject().Add("a", 2)
+	if err := o.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	o.Close()
+	l.Close()
+	if w.flushes != 2 {
+		t.Errorf("have %d flushes want 2", w.flushes)
+	}
+	if got, want := w.String(), `[1,{"a":2}]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// Flushing goes through to a bufio.Writer and its primary with WithSinks.
+	var buf, secondary bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	j := NewBuilder(nil).WithSinks(bw, &secondary).Add("a", 1)
+	if buf.Len() != 0 {
+		t.Fatalf("have <%s> before Flush", buf.String())
+	}
+	if err := j.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `{"a":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// A writer that can't be flushed is a no-op.
+	buf.Reset()
+	if err := NewBuilder(&buf).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	w = errFlushRecorder{err: errors.New("flush failed")}
+	j = NewBuilder(&w).Add("a", 1)
+	if err := j.Flush(); err == nil || j.Err != err {
+		t.Errorf("have <%v> want it stored in Err", err)
+	}
+	j.Add("b", 2).Close()
+	if got, want := w.String(), `{"a":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestFlushEvery(t *testing.T) {
+	var w errFlushRecorder
+	l := NewListBuilder(&w).SetFlushEvery(10)
+	var flushedAt []int
+	for i := 0; i < 25; i++ {
+		if i%2 == 0 {
+			l.Add(i)
+		} else {
+			l.AddObject().Add("i", i).Close()
+		}
+		if len(flushedAt) < w.flushes {
+			flushedAt = append(flushedAt, i)
+		}
+	}
+	if l.Close(); l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	// The 10th and 20th elements are flushed as soon as they're closed.
+	if w.flushes != 2 || fmt.Sprint(flushedAt) != "[9 19]" {
+		t.Errorf("have %d flushes at %v want 2 at [9 19]", w.flushes, flushedAt)
+	}
+
+	// A scalar is flushed as soon as it's added.
+	w = errFlushRecorder{}
+	l = NewListBuilder(&w).SetFlushEvery(2).Add(1).AddString("a")
+	if w.flushes != 1 || w.String() != `[1,"a"` {
+		t.Errorf("have %d flushes of <%s> want 1 of <[1,\"a\">", w.flushes, w.String())
+	}
+
+	w = errFlushRecorder{err: errors.New("flush failed")}
+	l = NewListBuilder(&w).SetFlushEvery(1).Add(1).Add(2)
+	if l.Err != w.err {
+		t.Errorf("have <%v> want <%v>", l.Err, w.err)
+	}
+}
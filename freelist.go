@@ -0,0 +1,34 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// freeList holds the duplicate-key sets of the sub-builders passed to
+// AddObjectFunc callbacks once the callbacks have returned, so that later
+// callbacks in the same document can reuse them instead of allocating new maps.
+//
+// The builders themselves are always new, because the caller may still hold one
+// after its callback returns (and it must keep reporting ErrMutatedAfterClose).
+type freeList struct {
+	keys []map[string]struct{}
+}
+
+// getBuilder returns a new Builder for s, reusing a free key set if there is
+// one.
+func (s *stream) getBuilder() *Builder {
+	b := &Builder{s: s}
+	if n := len(s.free.keys); n > 0 {
+		b.keys = s.free.keys[n-1]
+		s.free.keys = s.free.keys[:n-1]
+	}
+	return b
+}
+
+// putBuilder makes the key set of b, which must be closed, available to
+// getBuilder.
+func (s *stream) putBuilder(b *Builder) {
+	if b.keys != nil {
+		clear(b.keys)
+		s.free.keys = append(s.free.keys, b.keys)
+		b.keys = nil
+	}
+}
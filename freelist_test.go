@@ -0,0 +1,107 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// nested adds a structure with depth levels of objects and lists, built by
+// callbacks.
+func nested(b *Builder, depth int) {
+	if depth == 0 {
+		b.AddInt("leaf", 1)
+		return
+	}
+	b.AddObjectFunc("o", func(b *Builder) error {
+		nested(b, depth-1)
+		return nil
+	})
+	b.AddListFunc("l", func(l *ListBuilder) error {
+		l.AddBool(true).AddObjectFunc(func(b *Builder) error {
+			nested(b, depth-1)
+			return nil
+		})
+		return nil
+	})
+}
+
+func TestFreeList(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetDetectDuplicateKeys(true)
+	nested(j, 2)
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	const leaf = `{"o":{"leaf":1},"l":[true,{"leaf":1}]}`
+	want := `{"o":` + leaf + `,"l":[true,` + leaf + `]}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// The key set of every sub-builder after the first at each level is reused,
+	// so detecting duplicates adds a fixed number of allocations to a document,
+	// however many callbacks it has.
+	allocs := func(callbacks int, detect bool) float64 {
+		return testing.AllocsPerRun(10, func() {
+			l := NewListBuilder(io.Discard).SetDetectDuplicateKeys(detect)
+			for i := 0; i < callbacks; i++ {
+				l.AddObjectFunc(func(b *Builder) error {
+					nested(b, 3)
+					return nil
+				})
+			}
+			if l.Close(); l.Err != nil {
+				t.Fatal(l.Err)
+			}
+		})
+	}
+	few := allocs(10, true) - allocs(10, false)
+	many := allocs(100, true) - allocs(100, false)
+	if few <= 0 || many != few {
+		t.Errorf("have %v extra allocs for 10 callbacks and %v for 100 want the same", few, many)
+	}
+}
+
+func TestFreeListRetainedBuilder(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetDetectDuplicateKeys(true)
+	var retained *Builder
+	j.AddObjectFunc("a", func(b *Builder) error {
+		retained = b
+		b.AddInt("x", 1)
+		return nil
+	})
+	j.AddObjectFunc("b", func(b *Builder) error {
+		b.AddInt("y", 2)
+		if retained.AddInt("leak", 3); !errors.Is(retained.Err, ErrMutatedAfterClose) {
+			t.Errorf("expected ErrMutatedAfterClose got %v", retained.Err)
+		}
+		return nil
+	})
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":{"x":1},"b":{"y":2}}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func BenchmarkNestedFuncs(b *testing.B) {
+	b.ReportAllocs()
+	var buf bytes.Buffer
+	for i := 0; i < b.N; i++ {
+		j := NewBuilder(&buf)
+		for n := 0; n < benchLoad/10; n++ {
+			nested(j, 3)
+		}
+		if j.Close(); j.Err != nil {
+			b.Fatal(j.Err)
+		}
+		b.SetBytes(int64(buf.Len()))
+		buf.Reset()
+	}
+}
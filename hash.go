@@ -0,0 +1,25 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"hash"
+)
+
+// SetHash writes every byte of the output to h as well, so that after Close,
+// h.Sum(nil) is the digest of the exact output without a second pass over it.
+// It must be called before anything is added, otherwise Err is set.
+//
+// WithFinalizer must be called after SetHash for h to see the finalized
+// output. SetCanonical can be called in either order, since the canonical
+// form is only produced once output starts.
+func (b *Builder) SetHash(h hash.Hash) *Builder {
+	return b.Tee(h)
+}
+
+// SetHash writes every byte of the output to h as well, so that after Close,
+// h.Sum(nil) is the digest of the exact output without a second pass over it.
+// It must be called before anything is added, otherwise Err is set.
+func (b *ListBuilder) SetHash(h hash.Hash) *ListBuilder {
+	return b.Tee(h)
+}
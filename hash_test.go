@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestSetHash(t *testing.T) {
+	var buf bytes.Buffer
+	h := sha256.New()
+	j := NewBuilder(&buf).SetHash(h).Add("foo", "bar").AddObjectFunc("quz", f)
+	j.AddListFunc("quux", g).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if want := sha256.Sum256(buf.Bytes()); !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Errorf("have %x want %x", h.Sum(nil), want)
+	}
+
+	// With SetCanonical, the digest is of the canonical output, so it doesn't
+	// depend on the order the keys were added in.
+	digest := func(canonicalFirst bool, keys ...string) []byte {
+		var buf bytes.Buffer
+		h := sha256.New()
+		j := NewBuilder(&buf)
+		if canonicalFirst {
+			j.SetCanonical(true).SetHash(h)
+		} else {
+			j.SetHash(h).SetCanonical(true)
+		}
+		for _, key := range keys {
+			j.Add(key, key)
+		}
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if want := sha256.Sum256(buf.Bytes()); !bytes.Equal(h.Sum(nil), want[:]) {
+			t.Errorf("have %x want %x", h.Sum(nil), want)
+		}
+		return h.Sum(nil)
+	}
+	if a, b := digest(false, "a", "b", "c"), digest(true, "c", "a", "b"); !bytes.Equal(a, b) {
+		t.Errorf("have %x and %x want them equal", a, b)
+	}
+
+	buf.Reset()
+	h = sha256.New()
+	l := NewListBuilder(&buf).SetHash(h).Add(1).Close()
+	if want := sha256.Sum256([]byte(`[1]`)); !bytes.Equal(h.Sum(nil), want[:]) || l.Err != nil {
+		t.Errorf("have %x <%v> want %x", h.Sum(nil), l.Err, want)
+	}
+}
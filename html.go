@@ -0,0 +1,87 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SetEscapeHTML sets whether <, >, and & are escaped in strings (as \u003c,
+// \u003e, and \u0026), like json.Encoder.SetEscapeHTML. The default, like the
+// stdlib, is to escape them, which is only needed when the JSON is embedded in
+// HTML.
+//
+// It applies to every key and value added afterward, including in
+// sub-builders. The values of a builder from NewBuilderFromEncoder follow its
+// encoder's setting instead.
+func (b *Builder) SetEscapeHTML(on bool) *Builder {
+	b.s.setEscapeHTML(on)
+	return b
+}
+
+// SetEscapeHTML sets whether <, >, and & are escaped in strings (as \u003c,
+// \u003e, and \u0026), like json.Encoder.SetEscapeHTML. The default, like the
+// stdlib, is to escape them, which is only needed when the JSON is embedded in
+// HTML.
+//
+// It applies to every key and value added afterward, including in
+// sub-builders.
+func (b *ListBuilder) SetEscapeHTML(on bool) *ListBuilder {
+	b.s.setEscapeHTML(on)
+	return b
+}
+
+// SetEscapeForwardSlash sets whether / is escaped (as \/) in strings, as some
+// consumers require, for example when the JSON is embedded in a <script>
+// element. The default, like the stdlib, is to leave it unescaped.
+//
+// It applies to everything written afterward, including keys, raw values,
+// and sub-builders.
+func (b *Builder) SetEscapeForwardSlash(on bool) *Builder {
+	b.s.escapeSlash = on
+	return b
+}
+
+// SetEscapeForwardSlash sets whether / is escaped (as \/) in strings, as some
+// consumers require, for example when the JSON is embedded in a <script>
+// element. The default, like the stdlib, is to leave it unescaped.
+//
+// It applies to everything written afterward, including keys, raw values,
+// and sub-builders.
+func (b *ListBuilder) SetEscapeForwardSlash(on bool) *ListBuilder {
+	b.s.escapeSlash = on
+	return b
+}
+
+func (s *stream) setEscapeHTML(on bool) {
+	if s.keyCache != nil {
+		// The cached keys were escaped with the old setting.
+		s.keyCache = make(map[string][]byte)
+	}
+	if on {
+		s.html = nil
+	} else if s.html == nil {
+		s.html = &unescapedHTML{}
+		s.html.enc = json.NewEncoder(&s.html.buf)
+		s.html.enc.SetEscapeHTML(false)
+	}
+}
+
+// unescapedHTML marshals values without escaping HTML characters, which
+// json.Marshal can't do.
+type unescapedHTML struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+func (u *unescapedHTML) marshal(v interface{}) ([]byte, error) {
+	u.buf.Reset()
+	if err := u.enc.Encode(v); err != nil {
+		return nil, err
+	}
+	// Copy it out (without the newline Encode adds) because the buffer is
+	// reused.
+	raw := u.buf.Bytes()
+	return append([]byte(nil), raw[:len(raw)-1]...), nil
+}
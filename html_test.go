@@ -0,0 +1,88 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSetEscapeHTML(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(*Builder)
+	}{
+		{`{"url":"a\u003cb\u003e\u0026c"}`, func(j *Builder) { j.Add("url", "a<b>&c") }},
+		{`{"url":"a\u003cb\u003e\u0026c"}`, func(j *Builder) { j.SetEscapeHTML(true).Add("url", "a<b>&c") }},
+		{`{"url":"a<b>&c"}`, func(j *Builder) { j.SetEscapeHTML(false).Add("url", "a<b>&c") }},
+		{`{"<k>":{"s":["&"]},"l":["<"]}`, func(j *Builder) {
+			j.SetEscapeHTML(false)
+			j.AddObject("<k>").Add("s", []string{"&"}).Close()
+			j.AddListFunc("l", func(l *ListBuilder) error {
+				l.Add("<")
+				return nil
+			})
+		}},
+		{`{"a":"<","b":"\u003c"}`, func(j *Builder) {
+			j.SetEscapeHTML(false).Add("a", "<").SetEscapeHTML(true).Add("b", "<")
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := NewListBuilder(&buf).SetEscapeHTML(false).Add("&").Close().Err; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `["&"]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestEscapeForwardSlash(t *testing.T) {
+	const url = "https://example.com/a?b=1&c=<d>"
+	tests := []struct {
+		escapeSlash, escapeHTML bool
+		out                     string
+	}{
+		{false, true, `{"https://x/":"https://example.com/a?b=1\u0026c=\u003cd\u003e"}`},
+		{true, true, `{"https:\/\/x\/":"https:\/\/example.com\/a?b=1\u0026c=\u003cd\u003e"}`},
+		{true, false, `{"https:\/\/x\/":"https:\/\/example.com\/a?b=1&c=<d>"}`},
+		{false, false, `{"https://x/":"https://example.com/a?b=1&c=<d>"}`},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).SetEscapeForwardSlash(test.escapeSlash).SetEscapeHTML(test.escapeHTML)
+		if j.Add("https://x/", url).Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d: have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	// Slashes that are already escaped, or follow an escaped backslash, are
+	// handled, even split across writes.
+	var buf bytes.Buffer
+	l := NewListBuilder(&buf).SetEscapeForwardSlash(true).AddRaw([]byte(`"a\/b\\/c"`))
+	l.AddReader(iotest.OneByteReader(strings.NewReader(`"\\\/\\/"`))).AddStringSlice([]string{`\/`, "/"})
+	l.AddObject().SetEscapeNonASCII(true).AddString("é/", "/é").Close()
+	if l.Close(); l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	want := `["a\/b\\\/c","\\\/\\\/",["\\\/","\/"],{"\u00e9\/":"\/\u00e9"}]`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
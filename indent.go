@@ -0,0 +1,70 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+var indentedColonBytes = []byte(": ")
+
+// SetIndent makes the whole document pretty-printed, exactly like
+// json.MarshalIndent with the same prefix and indent would. Values marshaled by
+// the stdlib are re-indented to match their nesting, but those written
+// verbatim, like with AddRaw, are not.
+//
+// It must be called before anything is added.
+func (b *Builder) SetIndent(prefix, indent string) *Builder {
+	b.s.indent = &indentation{prefix: prefix, indent: indent}
+	return b
+}
+
+// SetIndent makes the whole document pretty-printed, exactly like
+// json.MarshalIndent with the same prefix and indent would. Values marshaled by
+// the stdlib are re-indented to match their nesting, but those written
+// verbatim, like with AddRaw, are not.
+//
+// It must be called before anything is added.
+func (b *ListBuilder) SetIndent(prefix, indent string) *ListBuilder {
+	b.s.indent = &indentation{prefix: prefix, indent: indent}
+	return b
+}
+
+type indentation struct {
+	prefix, indent string
+	// lines[d] is the line break before something at depth d.
+	lines [][]byte
+}
+
+// line returns the line break and leading whitespace for something nested
+// depth levels deep.
+func (i *indentation) line(depth int) []byte {
+	for len(i.lines) <= depth {
+		d := len(i.lines)
+		i.lines = append(i.lines, []byte("\n"+i.prefix+strings.Repeat(i.indent, d)))
+	}
+	return i.lines[depth]
+}
+
+// reindent writes the marshaled value raw, which is nested depth levels deep,
+// with the indentation of the rest of the document.
+func (i *indentation) reindent(s *stream, raw []byte, depth int) error {
+	if len(raw) == 0 || (raw[0] != '{' && raw[0] != '[') {
+		_, err := s.Write(raw)
+		return err
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}()
+
+	// Without its leading newline, line(depth) is the prefix json.Indent wants.
+	if err := json.Indent(buf, raw, string(i.line(depth)[1:]), i.indent); err != nil {
+		return err
+	}
+	_, err := s.Write(buf.Bytes())
+	return err
+}
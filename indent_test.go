@@ -0,0 +1,76 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSetIndent(t *testing.T) {
+	type point struct {
+		X, Y int
+		Tags []string
+	}
+	p := point{1, 2, []string{"a", "b"}}
+
+	tests := []struct {
+		prefix, indent string
+		fn             func(*Builder)
+	}{
+		{"", "  ", func(j *Builder) {}},
+		{"", "  ", func(j *Builder) { j.Add("a", 1) }},
+		{"", "  ", func(j *Builder) {
+			j.Add("a", "b").Add("p", p).Add("e", struct{}{}).Add("l", []int{})
+			o := j.AddObject("o").Add("c", 1)
+			o.AddObject("empty").Close()
+			o.Close()
+			l := j.AddList("l2").Add(p)
+			l.AddList().Close()
+			l.Add(3).Close()
+			j.AddObjectFunc("f", h).AddListFunc("g", g)
+		}},
+		{">", "\t", func(j *Builder) {
+			j.Add("p", p).AddObjectFunc("f", h)
+		}},
+		{"", "  ", func(j *Builder) {
+			j.AddPath([]string{"x", "y"}, p).AddPath([]string{"x", "z"}, 1)
+		}},
+	}
+	for i, test := range tests {
+		// The expected output is the compact output, re-indented.
+		var compact, want bytes.Buffer
+		j := NewBuilder(&compact)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if err := json.Indent(&want, compact.Bytes(), test.prefix, test.indent); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		j = NewBuilder(&buf).SetIndent(test.prefix, test.indent)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got := buf.String(); got != want.String() {
+			t.Errorf("%d have <%s> want <%s>", i, got, want.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	l := NewListBuilder(&buf).SetIndent("", " ").Add(p).AddObjectFunc(f).Close()
+	if l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	want, err := json.MarshalIndent([]interface{}{p, map[string]int{"baz": 7}}, "", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// Depth returns the current nesting depth of the document, which is shared by
+// every builder in it: 0 before the top-level object or list is opened, 1
+// inside it, 2 inside a sub-builder of it, and so on.
+func (b *Builder) Depth() int {
+	return b.s.depth
+}
+
+// Depth returns the current nesting depth of the document, which is shared by
+// every builder in it: 0 before the top-level object or list is opened, 1
+// inside it, 2 inside a sub-builder of it, and so on.
+func (b *ListBuilder) Depth() int {
+	return b.s.depth
+}
+
+// IsEmpty returns whether nothing has been added to this object yet. It's
+// always false after Close.
+func (b *Builder) IsEmpty() bool {
+	return b.state == startState
+}
+
+// IsEmpty returns whether nothing has been added to this list yet. It's always
+// false after Close.
+func (b *ListBuilder) IsEmpty() bool {
+	return b.state == startState
+}
+
+// Len returns the number of key value pairs added to this object, not counting
+// any in its sub-builders.
+func (b *Builder) Len() int {
+	return b.n
+}
+
+// Len returns the number of elements added to this list, not counting any in
+// its sub-builders.
+func (b *ListBuilder) Len() int {
+	return b.n
+}
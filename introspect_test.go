@@ -0,0 +1,85 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDepthAndIsEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	if !j.IsEmpty() || j.Depth() != 0 {
+		t.Errorf("have %t %d want true 0", j.IsEmpty(), j.Depth())
+	}
+	j.Add("a", 1)
+	if j.IsEmpty() || j.Depth() != 1 {
+		t.Errorf("have %t %d want false 1", j.IsEmpty(), j.Depth())
+	}
+	j.AddObjectFunc("o", func(b *Builder) error {
+		if !b.IsEmpty() || b.Depth() != 2 {
+			t.Errorf("have %t %d want true 2", b.IsEmpty(), b.Depth())
+		}
+		b.AddListFunc("l", func(l *ListBuilder) error {
+			if l.Depth() != 3 || j.Depth() != 3 {
+				t.Errorf("have %d %d want 3 3", l.Depth(), j.Depth())
+			}
+			l.Add(1)
+			if l.IsEmpty() {
+				t.Error("have true want false")
+			}
+			return nil
+		})
+		return nil
+	})
+	if j.Depth() != 1 {
+		t.Errorf("have %d want 1", j.Depth())
+	}
+	if j.Close(); j.Depth() != 0 || j.Err != nil {
+		t.Errorf("have %d <%v> want 0", j.Depth(), j.Err)
+	}
+
+	l := NewListBuilder(&buf)
+	if !l.IsEmpty() {
+		t.Error("have false want true")
+	}
+	if l.AddObject().Close(); l.IsEmpty() {
+		t.Error("have true want false")
+	}
+}
+
+func TestLen(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	if j.Len() != 0 {
+		t.Errorf("have %d want 0", j.Len())
+	}
+	j.Add("a", 1).AddAll("b", 2, "c", 3).AddString("d", "x")
+	j.AddObject("e").Add("x", 1).Add("y", 2).Close()
+	j.AddListFunc("f", func(l *ListBuilder) error {
+		l.Add(1).AddAll(2, 3).AddObject().Close()
+		if l.Len() != 4 {
+			t.Errorf("have %d want 4", l.Len())
+		}
+		return nil
+	})
+	if j.Len() != 6 {
+		t.Errorf("have %d want 6", j.Len())
+	}
+
+	// A pair that fails isn't counted.
+	j.Add("g", func() {})
+	if j.Err == nil || j.Len() != 6 {
+		t.Errorf("have %d <%v> want 6 and an error", j.Len(), j.Err)
+	}
+
+	// Neither is one that's discarded.
+	buf.Reset()
+	j = NewBuilder(&buf).WithDiscardOnFuncError().Add("a", 1)
+	j.AddObjectFunc("o", func(*Builder) error { return errors.New("x") })
+	if j.Len() != 1 {
+		t.Errorf("have %d want 1", j.Len())
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WithJSNumberFormat formats float64 values exactly like JavaScript's
+// Number.prototype.toString, so that the output is byte-identical to what a
+// JavaScript service would produce for the same values. Notably, negative zero
+// is emitted as 0, and exponential notation is used only for magnitudes of at
+// least 1e21 or less than 1e-6.
+//
+// Non-finite values still fail. Only values passed directly to the builder are
+// affected.
+func (b *Builder) WithJSNumberFormat() *Builder {
+	b.s.jsNumbers = true
+	return b
+}
+
+// WithJSNumberFormat formats float64 values exactly like JavaScript's
+// Number.prototype.toString, so that the output is byte-identical to what a
+// JavaScript service would produce for the same values. Notably, negative zero
+// is emitted as 0, and exponential notation is used only for magnitudes of at
+// least 1e21 or less than 1e-6.
+//
+// Non-finite values still fail. Only values passed directly to the builder are
+// affected.
+func (b *ListBuilder) WithJSNumberFormat() *ListBuilder {
+	b.s.jsNumbers = true
+	return b
+}
+
+// jsNumber formats a finite f per the Number::toString algorithm in the
+// ECMAScript spec.
+func jsNumber(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	var sign string
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// The shortest digits that round trip, s, and the exponent n, such that f
+	// is 0.s * 10^n.
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp := e[:strings.IndexByte(e, 'e')], e[strings.IndexByte(e, 'e')+1:]
+	digits := strings.Replace(mantissa, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+	n, k := x+1, len(digits)
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+	expSign := "+"
+	if n-1 < 0 {
+		expSign = "-"
+	}
+	exp = strconv.Itoa(int(math.Abs(float64(n - 1))))
+	if k == 1 {
+		return sign + digits + "e" + expSign + exp
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + expSign + exp
+}
@@ -0,0 +1,60 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestJSNumberFormat(t *testing.T) {
+	// The expected outputs are from String(x) in node.
+	tests := []struct {
+		in  float64
+		out string
+	}{
+		{0, `0`},
+		{math.Copysign(0, -1), `0`},
+		{1, `1`},
+		{-1.5, `-1.5`},
+		{0.30000000000000004, `0.30000000000000004`},
+		{123456789, `123456789`},
+		{1e20, `100000000000000000000`},
+		{123e18, `123000000000000000000`},
+		{999999999999999900000, `999999999999999900000`},
+		{1e21, `1e+21`},
+		{1.5e21, `1.5e+21`},
+		{-1e21, `-1e+21`},
+		{1.2345e300, `1.2345e+300`},
+		{math.MaxFloat64, `1.7976931348623157e+308`},
+		{0.000001, `0.000001`},
+		{0.0000012345, `0.0000012345`},
+		{1e-7, `1e-7`},
+		{-1.5e-7, `-1.5e-7`},
+		{5e-324, `5e-324`},
+		{100, `100`},
+		{12.5, `12.5`},
+	}
+	for _, test := range tests {
+		if got := jsNumber(test.in); got != test.out {
+			t.Errorf("%v: have <%s> want <%s>", test.in, got, test.out)
+		}
+	}
+
+	var buf bytes.Buffer
+	j := NewListBuilder(&buf).WithJSNumberFormat().
+		AddAll(math.Copysign(0, -1), 1e21, 1e-7, float32(1.5), 1).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `[0,1e+21,1e-7,1.5,1]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	NewListBuilder(&buf).Add(math.Copysign(0, -1)).Close()
+	if got, want := buf.String(), `[-0]`; got != want {
+		t.Errorf("have <%s> want <%s> without WithJSNumberFormat", got, want)
+	}
+}
@@ -7,10 +7,12 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"reflect"
 )
 
@@ -27,6 +29,13 @@ var closeBracketBytes = []byte{']'}
 var colonBytes = []byte{':'}
 var commaBytes = []byte{','}
 
+// ErrMutatedAfterClose is wrapped by the error stored in Err when a Builder or
+// ListBuilder is used after it's been closed. Check for it with errors.Is.
+var ErrMutatedAfterClose = errors.New("mutated after Close()")
+
+var errBuilderMutatedAfterClose = fmt.Errorf("Builder %w", ErrMutatedAfterClose)
+var errListBuilderMutatedAfterClose = fmt.Errorf("ListBuilder %w", ErrMutatedAfterClose)
+
 // BuilderFunc represents the creation of a JSON object.
 type BuilderFunc func(*Builder) error
 
@@ -35,18 +44,53 @@ type ListBuilderFunc func(*ListBuilder) error
 
 // A Builder writes JSON objects to an output stream, without needing it all to
 // be in memory at once.
+//
+// The first error encountered is stored in Err, after which every method is a
+// no-op.
 type Builder struct {
-	state writerState
-	w     io.Writer
-	e     encoder
-	subB  builderCommon
-	Err   error
+	state  writerState
+	opened bool
+	s      *stream
+	subB   builderCommon
+	n      int
+	paths  *pathNode
+	req    *requiredKeys
+	keys   map[string]struct{}
+	Err    error
+
+	// pending, if set, holds this sub-builder's output until it's closed.
+	pending *pendingSub
+
+	// prefix, if set, is written before the opening brace.
+	prefix []byte
+
+	// afterClose, if set, is run after the closing brace is written.
+	afterClose func() error
+
+	// root is whether this is a top-level builder, which owns its stream.
+	root bool
 }
 
 // NewBuilder returns a new encoder that writes to w.
+//
+// Nothing, not even the opening brace, is written until the first value is
+// added or Close is called. Until then, options that need to see the whole
+// document, like WithSinks and Tee, can still be set.
 func NewBuilder(w io.Writer) *Builder {
-	b := &Builder{startState, w, newEncoder(w), nil, nil}
-	b.init()
+	return &Builder{s: newStream(w), root: true}
+}
+
+// NewBuilderFromEncoder returns a new encoder that writes to w, but encodes
+// values with an encoder from makeEncoder so that they respect its SetEscapeHTML
+// and SetIndent settings. makeEncoder is called once, with the writer the
+// encoder must write to. It takes a constructor rather than a configured
+// *json.Encoder because an Encoder's writer can't be changed, and its settings
+// can't be read back to apply them to one that writes to the builder.
+//
+// Keys are escaped by the builder itself, following its own SetEscapeHTML.
+func NewBuilderFromEncoder(w io.Writer, makeEncoder func(io.Writer) *json.Encoder) *Builder {
+	b := NewBuilder(w)
+	b.s.e = newConfiguredEncoder(b.s, makeEncoder)
 	return b
 }
 
@@ -54,12 +98,31 @@ func (b *Builder) init() {
 	if b.state != startState {
 		b.Err = errors.New("Builder init'd after being mutated")
 	}
+	if b.root && b.s.canonical {
+		// Installed last, so that it's closest to the document and anything
+		// else that transforms or copies the output sees the canonical form.
+		b.WithFinalizer(canonicalize)
+	}
+	b.opened = true
+	b.s.enter()
+	if b.prefix != nil {
+		b.write(b.prefix)
+	}
 	b.write(openBraceBytes)
 }
 
+// open writes the opening delimiter if it hasn't been already. The root
+// builder defers this so that settings that wrap or copy the output can still
+// be made after construction without missing the first byte.
+func (b *Builder) open() {
+	if !b.opened {
+		b.init()
+	}
+}
+
 func (b *Builder) write(x []byte) {
 	if b.Err == nil {
-		_, b.Err = b.w.Write(x)
+		_, b.Err = b.s.Write(x)
 	}
 }
 
@@ -68,39 +131,107 @@ func (b *Builder) checkSub() error {
 		if err := b.subB.err(); err != nil {
 			b.Err = err
 		} else if !b.subB.closed() {
-			b.Err = errors.New("A sub-Builder was not closed")
+			if b.s.autoCloseSubBuilders {
+				b.Err = b.subB.close()
+			} else {
+				b.Err = errors.New("A sub-Builder was not closed")
+			}
 		}
 		b.subB = nil
 	}
 	return b.Err
 }
 
+// done returns true if b has failed or been closed, in which case every method
+// is a no-op. Using b after Close sets Err to an error wrapping
+// ErrMutatedAfterClose.
+func (b *Builder) done() bool {
+	if b.Err == nil && b.state == closedState {
+		b.Err = errBuilderMutatedAfterClose
+	}
+	return b.Err != nil
+}
+
 func (b *Builder) preadd(key string) error {
-	if b.state == closedState {
-		b.Err = errors.New("Builder mutated after Close()")
+	return b.preaddKey(key, nil)
+}
+
+// preaddKey is preadd, but if rawKey is non-nil, it's the key instead, and it's
+// written without being converted to a string unless a key check needs it.
+func (b *Builder) preaddKey(key string, rawKey []byte) error {
+	if b.done() {
+		return b.Err
+	}
+	if b.s.ctx != nil {
+		if b.Err = b.s.ctx.Err(); b.Err != nil {
+			return b.Err
+		}
 	}
+	b.open()
 	if err := b.checkSub(); err != nil {
 		return err
 	}
+	if rawKey != nil && b.checksKeys() {
+		key, rawKey = string(rawKey), nil
+	}
+	key = b.s.mapKey(key)
+	if b.Err = b.s.checkKey(key); b.Err != nil {
+		return b.Err
+	}
+	if b.s.detectDuplicates {
+		if b.Err = b.checkDuplicate(key); b.Err != nil {
+			return b.Err
+		}
+	}
+	if b.req != nil {
+		b.req.seen[key] = true
+	}
 
 	if b.state == startState {
 		b.state = openedState
 	} else {
 		b.write(commaBytes)
 	}
+	if b.s.indent != nil {
+		b.write(b.s.indent.line(b.s.depth))
+	}
 
-	b.Err = b.e.encode(key)
-	b.write(colonBytes)
+	if b.Err == nil {
+		if rawKey != nil {
+			b.Err = encodeKey(b.s, rawKey)
+		} else {
+			b.Err = encodeKey(b.s, key)
+		}
+	}
+	if b.s.indent != nil {
+		b.write(indentedColonBytes)
+	} else {
+		b.write(colonBytes)
+	}
+	if b.Err == nil {
+		b.n++
+		b.s.stats.Adds++
+	}
 	return b.Err
 }
 
 // Add emits a single key value pair to the stream.
+//
+// If value can't be marshaled, Err is set and nothing is written.
 func (b *Builder) Add(key string, value interface{}) *Builder {
+	if b.done() {
+		return b
+	}
+	v, err := b.s.marshal(value)
+	if err != nil {
+		b.Err = err
+		return b
+	}
 	if b.preadd(key) != nil {
 		return b
 	}
 
-	b.Err = b.e.encode(value)
+	b.Err = b.s.writeValue(v)
 	return b
 }
 
@@ -109,6 +240,9 @@ func (b *Builder) Add(key string, value interface{}) *Builder {
 // The args represent a key, then a value, then a key, and so on. There must be
 // an even number of args and the keys must all be strings.
 func (b *Builder) AddAll(args ...interface{}) *Builder {
+	if b.done() {
+		return b
+	}
 	if len(args)%2 != 0 {
 		b.Err = errors.New("AddAll takes an even number of args")
 		return b
@@ -126,10 +260,17 @@ func (b *Builder) AddAll(args ...interface{}) *Builder {
 
 // AddObject returns a builder for a JSON object value with the given key.
 //
-// Close() must be called on the sub-object before using this builder again.
+// Close() must be called on the sub-object before using this builder again. If
+// this builder has already failed, the returned builder carries the same Err.
 func (b *Builder) AddObject(key string) *Builder {
-	b.preadd(key)
-	subB := &Builder{0, b.w, b.e, nil, nil}
+	if b.descend() != nil {
+		return &Builder{state: closedState, s: b.s, Err: b.Err}
+	}
+	p, err := b.preaddSub(key)
+	if err != nil {
+		return &Builder{state: closedState, s: b.s, Err: b.Err}
+	}
+	subB := &Builder{s: b.s, pending: p}
 	subB.init()
 	b.subB = subB
 	return subB
@@ -137,10 +278,17 @@ func (b *Builder) AddObject(key string) *Builder {
 
 // AddList returns a builder for a JSON list value with the given key.
 //
-// Close() must be called on the sub-list before using this builder again.
+// Close() must be called on the sub-list before using this builder again. If
+// this builder has already failed, the returned builder carries the same Err.
 func (b *Builder) AddList(key string) *ListBuilder {
-	b.preadd(key)
-	subB := &ListBuilder{0, b.w, b.e, nil, nil}
+	if b.descend() != nil {
+		return &ListBuilder{state: closedState, s: b.s, Err: b.Err}
+	}
+	p, err := b.preaddSub(key)
+	if err != nil {
+		return &ListBuilder{state: closedState, s: b.s, Err: b.Err}
+	}
+	subB := &ListBuilder{s: b.s, pending: p}
 	subB.init()
 	b.subB = subB
 	return subB
@@ -148,50 +296,71 @@ func (b *Builder) AddList(key string) *ListBuilder {
 
 // AddObjectFunc emits a JSON object value (computed from f) with the given key.
 func (b *Builder) AddObjectFunc(key string, f BuilderFunc) *Builder {
+	if b.descend() != nil {
+		return b
+	}
+	if b.s.discardOnFuncError {
+		return b.addFuncOrDiscard(key, func() (fnErr, err error) { return b.s.objectFuncErrs(f) })
+	}
 	if b.preadd(key) != nil {
 		return b
 	}
 
-	subB := Builder{0, b.w, b.e, nil, nil}
-	subB.init()
-	b.Err = f(&subB)
-	if b.Err == nil {
-		b.Err = subB.Err
-	}
-	subB.Close()
+	b.Err = b.s.objectFunc(f)
 	return b
 }
 
 // AddListFunc emits a JSON list value (computed from f) with the given key.
 func (b *Builder) AddListFunc(key string, f ListBuilderFunc) *Builder {
+	if b.descend() != nil {
+		return b
+	}
+	if b.s.discardOnFuncError {
+		return b.addFuncOrDiscard(key, func() (fnErr, err error) { return b.s.listFuncErrs(f) })
+	}
 	if b.preadd(key) != nil {
 		return b
 	}
 
-	subB := ListBuilder{0, b.w, b.e, nil, nil}
-	subB.init()
-	b.Err = f(&subB)
-	if b.Err == nil {
-		b.Err = subB.Err
-	}
-	subB.Close()
+	b.Err = b.s.listFunc(f)
 	return b
 }
 
 // Close finalizes this JSON object and must be called for it to be complete.
 //
-// After Close is called, nothing else on this object may be called except Err.
+// After Close is called, every method is a no-op that sets Err, if it isn't
+// already set, to an error wrapping ErrMutatedAfterClose.
 func (b *Builder) Close() *Builder {
-	if b.state == closedState {
-		b.Err = errors.New("ListBuilder mutated after Close()")
+	if b.done() {
 		return b
 	}
+	b.open()
 	if b.checkSub() != nil {
 		return b
 	}
+	if paths := b.paths; paths != nil {
+		b.paths = nil
+		b.addPaths(paths)
+	}
+	if b.req != nil && b.Err == nil {
+		b.Err = b.req.check()
+	}
+	if b.s.indent != nil && b.state != startState {
+		b.write(b.s.indent.line(b.s.depth - 1))
+	}
 
 	b.write(closeBraceBytes)
 	b.state = closedState
+	b.s.exit()
+	if b.pending != nil && b.Err == nil {
+		b.Err = b.s.endPending(b.pending, true)
+	}
+	if b.afterClose != nil && b.Err == nil {
+		b.Err = b.afterClose()
+	}
+	if b.s.trailingNewline && b.s.depth == 0 {
+		b.write(newlineBytes)
+	}
 	return b
 }
 
@@ -203,33 +372,70 @@ func (b *Builder) err() error {
 	return b.Err
 }
 
+func (b *Builder) close() error {
+	return b.Close().Err
+}
+
 // A ListBuilder writes JSON lists to an output stream, without needing it all
 // to be in memory at once.
+//
+// The first error encountered is stored in Err, after which every method is a
+// no-op.
 type ListBuilder struct {
-	state writerState
-	w     io.Writer
-	e     encoder
-	subB  builderCommon
-	Err   error
+	state   writerState
+	opened  bool
+	s       *stream
+	subB    builderCommon
+	n       int
+	limit   *elementLimit
+	perLine bool
+	// flushEvery, if positive, flushes after every flushEvery elements.
+	flushEvery int
+	Err        error
+
+	// pending, if set, holds this sub-builder's output until it's closed.
+	pending *pendingSub
+
+	// afterClose, if set, is run after the closing bracket is written.
+	afterClose func() error
 }
 
 // NewListBuilder returns a new encoder that writes to w.
+//
+// Nothing, not even the opening bracket, is written until the first value is
+// added or Close is called. Until then, options that need to see the whole
+// document, like WithSinks and Tee, can still be set.
 func NewListBuilder(w io.Writer) *ListBuilder {
-	b := &ListBuilder{startState, w, newEncoder(w), nil, nil}
-	b.init()
-	return b
+	return &ListBuilder{s: newStream(w)}
 }
 
 func (b *ListBuilder) init() {
 	if b.state != startState {
 		b.Err = errors.New("ListBuilder init'd after being mutated")
 	}
+	b.opened = true
+	b.s.enter()
 	b.write(openBracketBytes)
 }
 
+// open writes the opening delimiter if it hasn't been already. The root
+// builder defers this so that settings that wrap or copy the output can still
+// be made after construction without missing the first byte.
+func (b *ListBuilder) open() {
+	if !b.opened {
+		b.init()
+	}
+}
+
 func (b *ListBuilder) write(x []byte) {
 	if b.Err == nil {
-		_, b.Err = b.w.Write(x)
+		_, b.Err = b.s.Write(x)
+	}
+}
+
+func (b *ListBuilder) encode(x interface{}) {
+	if b.Err == nil {
+		b.Err = b.s.encode(x)
 	}
 }
 
@@ -238,41 +444,93 @@ func (b *ListBuilder) checkSub() error {
 		if err := b.subB.err(); err != nil {
 			b.Err = err
 		} else if !b.subB.closed() {
-			b.Err = errors.New("A sub-Builder was not closed")
+			if b.s.autoCloseSubBuilders {
+				b.Err = b.subB.close()
+			} else {
+				b.Err = errors.New("A sub-Builder was not closed")
+			}
 		}
 		b.subB = nil
 	}
 	return b.Err
 }
 
+// done returns true if b has failed or been closed, in which case every method
+// is a no-op. Using b after Close sets Err to an error wrapping
+// ErrMutatedAfterClose.
+func (b *ListBuilder) done() bool {
+	if b.Err == nil && b.state == closedState {
+		b.Err = errListBuilderMutatedAfterClose
+	}
+	return b.Err != nil
+}
+
 func (b *ListBuilder) preadd() error {
-	if b.state == closedState {
-		b.Err = errors.New("ListWriter mutated after Close()")
+	if b.done() {
+		return b.Err
 	}
+	if b.s.ctx != nil {
+		if b.Err = b.s.ctx.Err(); b.Err != nil {
+			return b.Err
+		}
+	}
+	b.open()
 	if err := b.checkSub(); err != nil {
 		return err
 	}
+	if b.limit != nil && b.n >= b.limit.max {
+		b.limit.truncated = true
+		return errTruncated
+	}
 
+	b.separate()
+	if b.Err == nil {
+		b.n++
+		b.s.stats.Adds++
+	}
+	return b.Err
+}
+
+// separate writes whatever precedes the next element.
+func (b *ListBuilder) separate() {
 	if b.state == startState {
 		b.state = openedState
 	} else {
 		b.write(commaBytes)
 	}
-	return b.Err
+	if b.s.indent != nil {
+		b.write(b.s.indent.line(b.s.depth))
+	} else if b.perLine {
+		b.write(elementIndentBytes)
+	}
 }
 
 // Add emits a single value to the stream.
+//
+// If value can't be marshaled, Err is set and nothing is written.
 func (b *ListBuilder) Add(value interface{}) *ListBuilder {
+	if b.done() {
+		return b
+	}
+	v, err := b.s.marshal(value)
+	if err != nil {
+		b.Err = err
+		return b
+	}
 	if b.preadd() != nil {
 		return b
 	}
 
-	b.Err = b.e.encode(value)
+	b.Err = b.s.writeValue(v)
+	b.endElement()
 	return b
 }
 
 // AddAll emits many values to the stream.
 func (b *ListBuilder) AddAll(args ...interface{}) *ListBuilder {
+	if b.done() {
+		return b
+	}
 	for i := 0; i < len(args) && b.Err == nil; i++ {
 		b.Add(args[i])
 	}
@@ -282,22 +540,45 @@ func (b *ListBuilder) AddAll(args ...interface{}) *ListBuilder {
 // AddObject returns a builder for a JSON object value inserted as the next
 // element.
 //
-// Close() must be called on the sub-object before using this builder again.
+// Close() must be called on the sub-object before using this builder again. If
+// this builder has already failed, the returned builder carries the same Err.
 func (b *ListBuilder) AddObject() *Builder {
-	if b.preadd() != nil {
-		return nil
+	if b.descend() != nil {
+		return &Builder{state: closedState, s: b.s, Err: b.Err}
+	}
+	p, err := b.preaddSub()
+	if err == errTruncated {
+		return NewBuilder(ioutil.Discard)
+	} else if err != nil {
+		return &Builder{state: closedState, s: b.s, Err: b.Err}
+	}
+	subB := &Builder{s: b.s, pending: p}
+	if b.flushEvery > 0 {
+		subB.afterClose = b.endElement
 	}
-	subB := &Builder{0, b.w, b.e, nil, nil}
 	subB.init()
+	b.subB = subB
 	return subB
 }
 
 // AddList returns a builder for a JSON list value inserted as the next element.
 //
-// Close() must be called on the sub-list before using this builder again.
+// Close() must be called on the sub-list before using this builder again. If
+// this builder has already failed, the returned builder carries the same Err.
 func (b *ListBuilder) AddList() *ListBuilder {
-	b.preadd()
-	subB := &ListBuilder{0, b.w, b.e, nil, nil}
+	if b.descend() != nil {
+		return &ListBuilder{state: closedState, s: b.s, Err: b.Err}
+	}
+	p, err := b.preaddSub()
+	if err == errTruncated {
+		return NewListBuilder(ioutil.Discard)
+	} else if err != nil {
+		return &ListBuilder{state: closedState, s: b.s, Err: b.Err}
+	}
+	subB := &ListBuilder{s: b.s, pending: p}
+	if b.flushEvery > 0 {
+		subB.afterClose = b.endElement
+	}
 	subB.init()
 	b.subB = subB
 	return subB
@@ -306,50 +587,72 @@ func (b *ListBuilder) AddList() *ListBuilder {
 // AddObjectFunc emits a JSON object value (computed from f) as the next
 // element.
 func (b *ListBuilder) AddObjectFunc(f BuilderFunc) *ListBuilder {
+	if b.descend() != nil {
+		return b
+	}
+	if b.s.discardOnFuncError {
+		return b.addFuncOrDiscard(func() (fnErr, err error) { return b.s.objectFuncErrs(f) })
+	}
 	if b.preadd() != nil {
 		return b
 	}
 
-	subB := Builder{0, b.w, b.e, nil, nil}
-	subB.init()
-	b.Err = f(&subB)
-	if b.Err == nil {
-		b.Err = subB.Err
-	}
-	subB.Close()
+	b.Err = b.s.objectFunc(f)
+	b.endElement()
 	return b
 }
 
 // AddListFunc emits a JSON list value (computed from f) as the next element.
 func (b *ListBuilder) AddListFunc(f ListBuilderFunc) *ListBuilder {
+	if b.descend() != nil {
+		return b
+	}
+	if b.s.discardOnFuncError {
+		return b.addFuncOrDiscard(func() (fnErr, err error) { return b.s.listFuncErrs(f) })
+	}
 	if b.preadd() != nil {
 		return b
 	}
 
-	subB := ListBuilder{0, b.w, b.e, nil, nil}
-	subB.init()
-	b.Err = f(&subB)
-	if b.Err == nil {
-		b.Err = subB.Err
-	}
-	subB.Close()
+	b.Err = b.s.listFunc(f)
+	b.endElement()
 	return b
 }
 
 // Close finalizes this JSON object and must be called for it to be complete.
 //
-// After Close is called, nothing else on this object may be called except Err.
+// After Close is called, every method is a no-op that sets Err, if it isn't
+// already set, to an error wrapping ErrMutatedAfterClose.
 func (b *ListBuilder) Close() *ListBuilder {
-	if b.state == closedState {
-		b.Err = errors.New("ListWriter mutated after Close()")
+	if b.done() {
 		return b
 	}
+	b.open()
 	if b.checkSub() != nil {
 		return b
 	}
+	if b.limit != nil && b.limit.truncated && b.limit.marker != nil {
+		b.separate()
+		b.encode(b.limit.marker)
+	}
+	if b.s.indent != nil && b.state != startState {
+		b.write(b.s.indent.line(b.s.depth - 1))
+	} else if b.perLine && b.state != startState {
+		b.write(newlineBytes)
+	}
 
 	b.write(closeBracketBytes)
 	b.state = closedState
+	b.s.exit()
+	if b.pending != nil && b.Err == nil {
+		b.Err = b.s.endPending(b.pending, true)
+	}
+	if b.afterClose != nil && b.Err == nil {
+		b.Err = b.afterClose()
+	}
+	if b.s.trailingNewline && b.s.depth == 0 {
+		b.write(newlineBytes)
+	}
 	return b
 }
 
@@ -361,9 +664,63 @@ func (b *ListBuilder) err() error {
 	return b.Err
 }
 
+func (b *ListBuilder) close() error {
+	return b.Close().Err
+}
+
+// objectFunc emits the JSON object computed from f.
+func (s *stream) objectFunc(f BuilderFunc) error {
+	fnErr, err := s.objectFuncErrs(f)
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
+// objectFuncErrs runs f with a new sub-builder. It returns the error f
+// returned (or panicked with) separately from the sub-builder's own, which
+// comes from the output or a value rather than from f.
+func (s *stream) objectFuncErrs(f BuilderFunc) (fnErr, err error) {
+	if err := s.enterFunc(); err != nil {
+		return nil, err
+	}
+	defer s.exitFunc()
+
+	subB := s.getBuilder()
+	defer s.putBuilder(subB)
+	subB.init()
+	fnErr = s.call(func() error { return f(subB) })
+	subB.Close()
+	return fnErr, subB.Err
+}
+
+// listFunc emits the JSON list computed from f.
+func (s *stream) listFunc(f ListBuilderFunc) error {
+	fnErr, err := s.listFuncErrs(f)
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
+// listFuncErrs is objectFuncErrs for a list.
+func (s *stream) listFuncErrs(f ListBuilderFunc) (fnErr, err error) {
+	if err := s.enterFunc(); err != nil {
+		return nil, err
+	}
+	defer s.exitFunc()
+
+	subB := ListBuilder{s: s}
+	subB.init()
+	fnErr = s.call(func() error { return f(&subB) })
+	subB.Close()
+	return fnErr, subB.Err
+}
+
 type builderCommon interface {
 	closed() bool
 	err() error
+	close() error
 }
 
 type encoder interface {
@@ -379,6 +736,23 @@ func newEncoder(w io.Writer) encoder {
 	// return newStreamingEncoder(w)
 }
 
+// ValueSize returns the number of bytes v occupies when it is added to a
+// builder.
+//
+// It lets callers check a value against a size budget before adding it.
+func ValueSize(v interface{}) (int, error) {
+	var w countingWriter
+	err := newEncoder(&w).encode(v)
+	return int(w), err
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
 type basicEncoder struct {
 	io.Writer
 }
@@ -386,7 +760,7 @@ type basicEncoder struct {
 func (b basicEncoder) encode(arg interface{}) error {
 	bytes, err := json.Marshal(arg)
 	if err != nil {
-		return nil
+		return err
 	}
 	_, err = b.Write(bytes)
 	return err
@@ -403,13 +777,48 @@ func (b streamingEncoder) encode(arg interface{}) error {
 	return b.Encode(arg)
 }
 
+// configuredEncoder encodes values with a json.Encoder from
+// NewBuilderFromEncoder. Each value is encoded into buf first, so that one that
+// fails is rejected before its key is written.
+type configuredEncoder struct {
+	w   io.Writer
+	enc *json.Encoder
+	buf *bytes.Buffer
+}
+
+func newConfiguredEncoder(w io.Writer, makeEncoder func(io.Writer) *json.Encoder) configuredEncoder {
+	var buf bytes.Buffer
+	return configuredEncoder{w: w, enc: makeEncoder(&buf), buf: &buf}
+}
+
+// marshal returns v encoded, without the newline json.Encoder ends it with.
+func (c configuredEncoder) marshal(v interface{}) (verbatim, error) {
+	c.buf.Reset()
+	if err := c.enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return append(verbatim(nil), bytes.TrimSuffix(c.buf.Bytes(), newlineBytes)...), nil
+}
+
+func (c configuredEncoder) encode(arg interface{}) error {
+	raw, err := c.marshal(arg)
+	if err != nil {
+		return err
+	}
+	_, err = c.w.Write(raw)
+	return err
+}
+
 type trimTrailingNewlineWriter struct {
 	w io.Writer
 }
 
 func (h trimTrailingNewlineWriter) Write(p []byte) (n int, err error) {
-	if p[len(p)-1] == '\n' {
-		return h.w.Write(p[0 : len(p)-1])
+	if len(p) > 0 && p[len(p)-1] == '\n' {
+		if n, err = h.w.Write(p[0 : len(p)-1]); err == nil {
+			n = len(p)
+		}
+		return n, err
 	}
 	return h.w.Write(p)
 }
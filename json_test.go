@@ -5,8 +5,13 @@ package json
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -136,7 +141,7 @@ func TestBuilder(t *testing.T) {
 		jsonTest.fn(j)
 		j.Close()
 		if j.Err != nil {
-			t.Errorf("%d Unexpected error <%d>", j.Err.Error())
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
 		}
 		if got := buf.String(); got != jsonTest.out {
 			t.Errorf("%d have <%s> want <%s>", i, got, jsonTest.out)
@@ -179,7 +184,7 @@ func TestListBuilder(t *testing.T) {
 		jsonListTest.fn(j)
 		j.Close()
 		if j.Err != nil {
-			t.Errorf("%d Unexpected error <%d>", j.Err.Error())
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
 		}
 		if got := buf.String(); got != jsonListTest.out {
 			t.Errorf("%d have <%s> want <%s>", i, got, jsonListTest.out)
@@ -196,3 +201,352 @@ func TestUnclosedSubBuilder(t *testing.T) {
 		t.Error("Expected error")
 	}
 }
+
+func TestUnclosedSubBuilderInList(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewListBuilder(&buf).Add(1)
+	j.AddObject().Add("2", 2)
+	j.Add(3)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+}
+
+type failingWriter struct {
+	writes int
+	failAt int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes >= w.failAt {
+		return 0, errors.New("write failed")
+	}
+	return len(p), nil
+}
+
+func TestNoWritesAfterError(t *testing.T) {
+	w := &failingWriter{failAt: 2}
+	j := NewBuilder(w).Add("1", 1)
+	if j.Err == nil {
+		t.Fatal("Expected error")
+	}
+	j.Add("2", 2).AddAll("3", 3)
+	j.AddObject("4").Add("5", 5).Close()
+	j.AddList("6").Add(7).Close()
+	j.AddObjectFunc("8", f).AddListFunc("9", g).Close()
+	if w.writes != 2 {
+		t.Errorf("have %d writes want 2", w.writes)
+	}
+
+	w = &failingWriter{failAt: 2}
+	l := NewListBuilder(w).Add(1)
+	if l.Err == nil {
+		t.Fatal("Expected error")
+	}
+	l.Add(2).AddAll(3, 4)
+	l.AddObject().Add("5", 5).Close()
+	l.AddList().Add(6).Close()
+	l.AddObjectFunc(f).AddListFunc(g).Close()
+	if w.writes != 2 {
+		t.Errorf("have %d writes want 2", w.writes)
+	}
+}
+
+func TestValueSize(t *testing.T) {
+	v := struct {
+		A int      `json:"a"`
+		B string   `json:"b"`
+		C []string `json:"c"`
+	}{7, "bar\n", []string{"<", ">"}}
+
+	size, err := ValueSize(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	j := NewListBuilder(&buf).Add(v).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if want := buf.Len() - len("[]"); size != want {
+		t.Errorf("have %d want %d", size, want)
+	}
+}
+
+func TestNewBuilderFromEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	noEscapeHTML := func(w io.Writer) *json.Encoder {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc
+	}
+	j := NewBuilderFromEncoder(&buf, noEscapeHTML).Add("a", "<b>&")
+	j.AddList("l").Add("<").Close()
+	j.Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":"<b>&","l":["<"]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if got, want := j.Stats().BytesWritten, int64(buf.Len()); got != want {
+		t.Errorf("BytesWritten: have %d want %d", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilderFromEncoder(&buf, noEscapeHTML).SetMaxBytes(8).Add("a", "<b>&").Close()
+	if j.Err == nil || buf.Len() > 8 {
+		t.Errorf("expected SetMaxBytes to be enforced, got <%s> %v", buf.String(), j.Err)
+	}
+
+	// A value that fails to encode doesn't leave its key behind.
+	buf.Reset()
+	j = NewBuilderFromEncoder(&buf, noEscapeHTML).Add("a", 1).Add("b", make(chan int))
+	if got, want := buf.String(), `{"a":1`; got != want || j.Err == nil {
+		t.Errorf("have <%s> <%v> want <%s> and an error", got, j.Err, want)
+	}
+
+	// AddPath encodes with the encoder's settings too.
+	buf.Reset()
+	j = NewBuilderFromEncoder(&buf, noEscapeHTML).AddPath([]string{"p", "q"}, "<").Close()
+	if got, want := buf.String(), `{"p":{"q":"<"}}`; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+
+	buf.Reset()
+	indented := func(w io.Writer) *json.Encoder {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc
+	}
+	j = NewBuilderFromEncoder(&buf, indented).Add("a", "<").Add("b", []int{1, 2}).Close()
+	var decoded struct {
+		A string
+		B []int
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("%s <%s>", err, buf.String())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`\u003c`)) || !bytes.Contains(buf.Bytes(), []byte("[\n  1,\n  2\n]")) {
+		t.Errorf("encoder settings not respected <%s>", buf.String())
+	}
+}
+
+func TestDeferredOpen(t *testing.T) {
+	var buf, tee bytes.Buffer
+	j := NewBuilder(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> before anything was added", buf.String())
+	}
+	j.Tee(&tee).Add("a", 1)
+	if got, want := tee.String(), `{"a":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	l := NewListBuilder(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> before anything was added", buf.String())
+	}
+	if l.Close(); buf.String() != `[]` {
+		t.Errorf("have <%s> want <[]>", buf.String())
+	}
+}
+
+func TestBuilderAsValue(t *testing.T) {
+	var buf bytes.Buffer
+	other := NewBuilder(&buf)
+	otherList := NewListBuilder(&buf)
+	for i, v := range []interface{}{other, *other, otherList, *otherList} {
+		j := NewBuilder(&buf).Add("a", v)
+		if j.Err == nil || !strings.Contains(j.Err.Error(), "Func") {
+			t.Errorf("%d Unexpected error <%v>", i, j.Err)
+		}
+		l := NewListBuilder(&buf).Add(v)
+		if l.Err == nil || !strings.Contains(l.Err.Error(), "Func") {
+			t.Errorf("%d Unexpected error <%v>", i, l.Err)
+		}
+	}
+}
+
+func TestMutatedAfterClose(t *testing.T) {
+	noop := func(*Builder) error { return nil }
+	noopList := func(*ListBuilder) error { return nil }
+	objectMethods := map[string]func(*Builder) error{
+		"Add":            func(b *Builder) error { return b.Add("a", 1).Err },
+		"AddAll":         func(b *Builder) error { return b.AddAll("a", 1).Err },
+		"AddObject":      func(b *Builder) error { return b.AddObject("a").Err },
+		"AddList":        func(b *Builder) error { return b.AddList("a").Err },
+		"AddObjectFunc":  func(b *Builder) error { return b.AddObjectFunc("a", noop).Err },
+		"AddListFunc":    func(b *Builder) error { return b.AddListFunc("a", noopList).Err },
+		"AddRaw":         func(b *Builder) error { return b.AddRaw("a", []byte("1")).Err },
+		"AddPath":        func(b *Builder) error { return b.AddPath([]string{"a"}, 1).Err },
+		"AddMap":         func(b *Builder) error { return b.AddMap("a", 1).Err },
+		"AddSparseArray": func(b *Builder) error { return b.AddSparseArray("a", 0, nil).Err },
+		"AddTriState":    func(b *Builder) error { return b.AddTriState("a", 2).Err },
+		"AddMoney":       func(b *Builder) error { return b.AddMoney("a", 1, "?").Err },
+		"AddError":       func(b *Builder) error { return b.AddError("a", nil).Err },
+		"Close":          func(b *Builder) error { return b.Close().Err },
+		"AddObjectFunc discarding": func(b *Builder) error {
+			return b.WithDiscardOnFuncError().AddObjectFunc("a", noop).Err
+		},
+	}
+	listMethods := map[string]func(*ListBuilder) error{
+		"Add":           func(b *ListBuilder) error { return b.Add(1).Err },
+		"AddAll":        func(b *ListBuilder) error { return b.AddAll(1).Err },
+		"AddObject":     func(b *ListBuilder) error { return b.AddObject().Err },
+		"AddList":       func(b *ListBuilder) error { return b.AddList().Err },
+		"AddObjectFunc": func(b *ListBuilder) error { return b.AddObjectFunc(noop).Err },
+		"AddListFunc":   func(b *ListBuilder) error { return b.AddListFunc(noopList).Err },
+		"AddRows":       func(b *ListBuilder) error { return b.AddRows([]map[string]interface{}{{}}, nil).Err },
+		"AddRaw":        func(b *ListBuilder) error { return b.AddRaw([]byte("1")).Err },
+		"Close":         func(b *ListBuilder) error { return b.Close().Err },
+		"AddObjectFunc discarding": func(b *ListBuilder) error {
+			return b.WithDiscardOnFuncError().AddObjectFunc(noop).Err
+		},
+	}
+
+	for name, fn := range objectMethods {
+		var buf bytes.Buffer
+		b := NewBuilder(&buf).Close()
+		if err := fn(b); !errors.Is(err, ErrMutatedAfterClose) {
+			t.Errorf("Builder.%s: have <%v> want <%v>", name, err, ErrMutatedAfterClose)
+		}
+		if buf.String() != "{}" {
+			t.Errorf("Builder.%s: wrote after Close <%s>", name, buf.String())
+		}
+
+		// An existing error is preserved.
+		buf.Reset()
+		b = NewBuilder(&buf).Close()
+		b.Err = errors.New("first")
+		if err := fn(b); err == nil || err.Error() != "first" {
+			t.Errorf("Builder.%s: have <%v> want <first>", name, err)
+		}
+	}
+	for name, fn := range listMethods {
+		var buf bytes.Buffer
+		b := NewListBuilder(&buf).Close()
+		if err := fn(b); !errors.Is(err, ErrMutatedAfterClose) {
+			t.Errorf("ListBuilder.%s: have <%v> want <%v>", name, err, ErrMutatedAfterClose)
+		}
+		if buf.String() != "[]" {
+			t.Errorf("ListBuilder.%s: wrote after Close <%s>", name, buf.String())
+		}
+
+		buf.Reset()
+		b = NewListBuilder(&buf).Close()
+		b.Err = errors.New("first")
+		if err := fn(b); err == nil || err.Error() != "first" {
+			t.Errorf("ListBuilder.%s: have <%v> want <first>", name, err)
+		}
+	}
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("failingMarshaler")
+}
+
+func TestUnmarshalableValue(t *testing.T) {
+	values := []interface{}{make(chan int), func() {}, failingMarshaler{}, math.NaN()}
+	for i, v := range values {
+		var buf bytes.Buffer
+		b := NewBuilder(&buf).Add("a", 1).Add("b", v)
+		if b.Err == nil {
+			t.Errorf("%d Builder expected an error for %T", i, v)
+		}
+		if got, want := buf.String(), `{"a":1`; got != want {
+			t.Errorf("%d Builder have <%s> want <%s>", i, got, want)
+		}
+
+		buf.Reset()
+		l := NewListBuilder(&buf).Add(1).Add(v)
+		if l.Err == nil {
+			t.Errorf("%d ListBuilder expected an error for %T", i, v)
+		}
+		if got, want := buf.String(), `[1`; got != want {
+			t.Errorf("%d ListBuilder have <%s> want <%s>", i, got, want)
+		}
+	}
+}
+
+func TestMutatedAfterCloseMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() error
+		err  string
+	}{
+		{"Builder double Close", func() error {
+			return NewBuilder(ioutil.Discard).Close().Close().Err
+		}, "Builder mutated after Close()"},
+		{"Builder Add after Close", func() error {
+			return NewBuilder(ioutil.Discard).Close().Add("a", 1).Err
+		}, "Builder mutated after Close()"},
+		{"ListBuilder double Close", func() error {
+			return NewListBuilder(ioutil.Discard).Close().Close().Err
+		}, "ListBuilder mutated after Close()"},
+		{"ListBuilder Add after Close", func() error {
+			return NewListBuilder(ioutil.Discard).Close().Add(1).Err
+		}, "ListBuilder mutated after Close()"},
+		{"sub-ListBuilder Add after Close", func() error {
+			var l *ListBuilder
+			NewBuilder(ioutil.Discard).AddListFunc("l", func(b *ListBuilder) error {
+				l = b
+				return nil
+			})
+			return l.Add(1).Err
+		}, "ListBuilder mutated after Close()"},
+	}
+	for _, test := range tests {
+		if err := test.fn(); err == nil || err.Error() != test.err {
+			t.Errorf("%s: have <%v> want <%s>", test.name, err, test.err)
+		}
+	}
+}
+
+// point marshals itself as a list instead of its fields.
+type point struct{ X, Y int }
+
+func (p point) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("[%d,%d]", p.X, p.Y)), nil
+}
+
+// ptrPoint only implements json.Marshaler with a pointer receiver.
+type ptrPoint struct{ X int }
+
+func (p *ptrPoint) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"x=%d"`, p.X)), nil
+}
+
+func TestMarshaler(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).Add("p", point{1, 2}).Add("pp", &point{3, 4}).Add("ptr", &ptrPoint{5})
+	j.AddObjectFunc("o", func(b *Builder) error {
+		b.AddListFunc("l", func(l *ListBuilder) error {
+			l.Add(point{6, 7}).Add([]point{{8, 9}})
+			return nil
+		})
+		return nil
+	})
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"p":[1,2],"pp":[3,4],"ptr":"x=5","o":{"l":[[6,7],[[8,9]]]}}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).AddObjectFunc("o", func(b *Builder) error {
+		b.Add("a", 1).Add("f", failingMarshaler{})
+		return nil
+	})
+	if j.Err == nil || !strings.Contains(j.Err.Error(), "failingMarshaler") {
+		t.Errorf("have <%v> want the MarshalJSON error", j.Err)
+	}
+	if got, want := buf.String(), `{"o":{"a":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"fmt"
+	"io"
+)
+
+var jsonpSuffixBytes = []byte(");")
+
+// NewJSONPBuilder returns a new encoder that writes a JSON object to w wrapped
+// in a JSONP call of callback, like cb({"a":1});. To prevent script injection,
+// callback may only contain ASCII letters, digits, _, $, and . (so that a
+// property like window.cb can be called); anything else sets Err.
+func NewJSONPBuilder(w io.Writer, callback string) *Builder {
+	b := NewBuilder(w)
+	if !isJSONPCallback(callback) {
+		b.Err = fmt.Errorf("Invalid JSONP callback %q", callback)
+		return b
+	}
+	b.prefix = []byte(callback + "(")
+	b.afterClose = func() error {
+		_, err := b.s.Write(jsonpSuffixBytes)
+		return err
+	}
+	return b
+}
+
+func isJSONPCallback(callback string) bool {
+	if callback == "" {
+		return false
+	}
+	for i := 0; i < len(callback); i++ {
+		switch c := callback[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '_' || c == '$' || c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONPBuilder(t *testing.T) {
+	tests := []struct {
+		callback string
+		out      string
+	}{
+		{"cb", `cb({"a":1,"l":[1,2,3]});`},
+		{"window.cb", `window.cb({"a":1,"l":[1,2,3]});`},
+		{"$jQuery_123", `$jQuery_123({"a":1,"l":[1,2,3]});`},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		j := NewJSONPBuilder(&buf, test.callback).Add("a", 1).AddListFunc("l", g).Close()
+		if j.Err != nil {
+			t.Errorf("%s: Unexpected error <%s>", test.callback, j.Err)
+			continue
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%s: have <%s> want <%s>", test.callback, got, test.out)
+		}
+	}
+
+	// With a finalizer, the suffix is still written last.
+	var buf bytes.Buffer
+	j := NewJSONPBuilder(&buf, "cb").SetCanonical(true).Add("b", 2).Add("a", 1).Close()
+	if got, want := buf.String(), `cb({"a":1,"b":2});`; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+
+	// The wrapper is written through the builder, so it's counted, limited,
+	// and teed like the rest of the output, and the trailing newline follows it.
+	buf.Reset()
+	var tee bytes.Buffer
+	j = NewJSONPBuilder(&buf, "cb").Tee(&tee).SetTrailingNewline(true).Add("a", 1).Close()
+	if got, want := buf.String(), "cb({\"a\":1});\n"; got != want || j.Err != nil {
+		t.Errorf("have <%q> <%v> want <%q>", got, j.Err, want)
+	}
+	if tee.String() != buf.String() {
+		t.Errorf("have <%q> teed want <%q>", tee.String(), buf.String())
+	}
+	if got, want := j.BytesWritten(), int64(buf.Len()); got != want {
+		t.Errorf("have %d bytes written want %d", got, want)
+	}
+	buf.Reset()
+	if j := NewJSONPBuilder(&buf, "cb").SetMaxBytes(8).Add("a", 1).Close(); j.Err != ErrMaxBytesExceeded {
+		t.Errorf("have <%v> want <%v>", j.Err, ErrMaxBytesExceeded)
+	}
+
+	for _, callback := range []string{"", "alert(1);cb", "cb<script>", "a b", "cb\n", "ñ"} {
+		buf.Reset()
+		j := NewJSONPBuilder(&buf, callback).Add("a", 1).Close()
+		if j.Err == nil || buf.Len() != 0 {
+			t.Errorf("%q: have <%s> <%v> want an error and no output", callback, buf.String(), j.Err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithKeyAllowlist restricts the keys that may be emitted to exactly (case
+// sensitively) the given ones. Adding any other key sets Err. It applies to
+// every object in the document, including nested ones.
+func (b *Builder) WithKeyAllowlist(keys []string) *Builder {
+	b.s.allowedKeys = make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		b.s.allowedKeys[key] = struct{}{}
+	}
+	return b
+}
+
+// SetKeyFunc sets a function that transforms every key before it's emitted,
+// like a conversion from Go-style names to snake_case. It applies to every
+// object in the document, including nested ones. Key checks (the allowlist,
+// duplicates, and required keys) see the transformed key. Nil, the default,
+// emits keys as given.
+func (b *Builder) SetKeyFunc(f func(string) string) *Builder {
+	b.s.keyFunc = f
+	return b
+}
+
+// SetKeyFunc sets a function that transforms every key before it's emitted,
+// like a conversion from Go-style names to snake_case. It applies to every
+// object in the document, including nested ones. Key checks (the allowlist,
+// duplicates, and required keys) see the transformed key. Nil, the default,
+// emits keys as given.
+func (b *ListBuilder) SetKeyFunc(f func(string) string) *ListBuilder {
+	b.s.keyFunc = f
+	return b
+}
+
+// mapKey returns key as it's emitted.
+func (s *stream) mapKey(key string) string {
+	if s.keyFunc == nil {
+		return key
+	}
+	return s.keyFunc(key)
+}
+
+// SetValidateKeys sets whether adding a key that isn't valid UTF-8 sets Err.
+// Otherwise, each invalid byte is emitted as U+FFFD, which silently changes
+// keys derived from binary data.
+func (b *Builder) SetValidateKeys(on bool) *Builder {
+	b.s.validateKeys = on
+	return b
+}
+
+// SetValidateKeys sets whether adding a key that isn't valid UTF-8 sets Err.
+// Otherwise, each invalid byte is emitted as U+FFFD, which silently changes
+// keys derived from binary data.
+func (b *ListBuilder) SetValidateKeys(on bool) *ListBuilder {
+	b.s.validateKeys = on
+	return b
+}
+
+// SetRejectEmptyKeys sets whether adding the empty key sets Err.
+func (b *Builder) SetRejectEmptyKeys(on bool) *Builder {
+	b.s.rejectEmptyKeys = on
+	return b
+}
+
+// SetRejectEmptyKeys sets whether adding the empty key sets Err.
+func (b *ListBuilder) SetRejectEmptyKeys(on bool) *ListBuilder {
+	b.s.rejectEmptyKeys = on
+	return b
+}
+
+// checkKey returns an error if key may not be emitted.
+func (s *stream) checkKey(key string) error {
+	if s.validateKeys && !utf8.ValidString(key) {
+		return fmt.Errorf("Key %q is not valid UTF-8", key)
+	}
+	if s.rejectEmptyKeys && key == "" {
+		return errors.New("Empty key")
+	}
+	if s.allowedKeys != nil {
+		if _, ok := s.allowedKeys[key]; !ok {
+			return fmt.Errorf("Key %q is not in the allowlist", key)
+		}
+	}
+	return nil
+}
+
+// SetDetectDuplicateKeys sets whether adding a key that was already added to
+// the same object sets Err. Each object, including nested ones, has its own set
+// of keys. The keys of an object are only tracked while this is on.
+func (b *Builder) SetDetectDuplicateKeys(on bool) *Builder {
+	b.s.detectDuplicates = on
+	return b
+}
+
+// SetDetectDuplicateKeys sets whether adding a key that was already added to
+// the same object sets Err. Each object, including nested ones, has its own set
+// of keys. The keys of an object are only tracked while this is on.
+func (b *ListBuilder) SetDetectDuplicateKeys(on bool) *ListBuilder {
+	b.s.detectDuplicates = on
+	return b
+}
+
+// checkDuplicate records key and returns an error if it was already recorded.
+func (b *Builder) checkDuplicate(key string) error {
+	if _, ok := b.keys[key]; ok {
+		return fmt.Errorf("Duplicate key %q", key)
+	}
+	if b.keys == nil {
+		b.keys = make(map[string]struct{})
+	}
+	b.keys[key] = struct{}{}
+	return nil
+}
+
+type requiredKeys struct {
+	keys []string
+	seen map[string]bool
+}
+
+// WithRequiredKeys makes Close set Err, naming the missing keys, unless every
+// one of keys was added to this object. Nested objects are unaffected.
+func (b *Builder) WithRequiredKeys(keys []string) *Builder {
+	b.req = &requiredKeys{keys, make(map[string]bool, len(keys))}
+	return b
+}
+
+func (r *requiredKeys) check() error {
+	var missing []string
+	for _, key := range r.keys {
+		if !r.seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Missing required keys: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// SetKeyCache sets whether each distinct key is escaped only once, the first
+// time it's added, and the result reused after that. This speeds up documents
+// that repeat the same keys many times, like one object per row of a table.
+// The cache is shared by the whole document and is unbounded, so it shouldn't
+// be used when keys are unbounded too, like ones from user input.
+func (b *Builder) SetKeyCache(on bool) *Builder {
+	b.s.setKeyCache(on)
+	return b
+}
+
+// SetKeyCache sets whether each distinct key is escaped only once, the first
+// time it's added, and the result reused after that. This speeds up documents
+// that repeat the same keys many times, like one object per row of a table.
+// The cache is shared by the whole document and is unbounded, so it shouldn't
+// be used when keys are unbounded too, like ones from user input.
+func (b *ListBuilder) SetKeyCache(on bool) *ListBuilder {
+	b.s.setKeyCache(on)
+	return b
+}
+
+func (s *stream) setKeyCache(on bool) {
+	if !on {
+		s.keyCache = nil
+	} else if s.keyCache == nil {
+		s.keyCache = make(map[string][]byte)
+	}
+}
+
+// checksKeys returns whether any option that inspects or transforms keys is
+// set.
+func (b *Builder) checksKeys() bool {
+	s := b.s
+	return s.keyFunc != nil || s.allowedKeys != nil || s.detectDuplicates || b.req != nil ||
+		s.validateKeys || s.rejectEmptyKeys
+}
+
+// AddBytesKey is Add, but with the key given as bytes, which are escaped
+// directly into the output without first being converted to a string. This
+// avoids an allocation per key when transcoding keys from another format.
+func (b *Builder) AddBytesKey(key []byte, value interface{}) *Builder {
+	if b.done() {
+		return b
+	}
+	v, err := b.s.marshal(value)
+	if err != nil {
+		b.Err = err
+		return b
+	}
+	if key == nil {
+		key = []byte{}
+	}
+	if b.preaddKey("", key) != nil {
+		return b
+	}
+
+	b.Err = b.s.writeValue(v)
+	return b
+}
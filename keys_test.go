@@ -0,0 +1,300 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestKeyAllowlist(t *testing.T) {
+	allowed := []string{"id", "name", "tags"}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithKeyAllowlist(allowed).Add("id", 1).Add("name", "x").Close()
+	if j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+	if got, want := buf.String(), `{"id":1,"name":"x"}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	for i, fn := range []func(*Builder){
+		func(j *Builder) { j.Add("Name", "x") },
+		func(j *Builder) { j.AddObject("tags").Add("nmae", "x").Close() },
+		func(j *Builder) { j.AddAll("id", 1, "tag", 2) },
+	} {
+		buf.Reset()
+		j := NewBuilder(&buf).WithKeyAllowlist(allowed)
+		fn(j)
+		if j.Close(); j.Err == nil {
+			t.Errorf("%d Expected error", i)
+		}
+	}
+}
+
+func TestRequiredKeys(t *testing.T) {
+	required := []string{"id", "name", "tags"}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithRequiredKeys(required)
+	j.Add("name", "x").AddPath([]string{"tags", "a"}, 1).AddObject("id").Add("other", 1).Close()
+	if j.Close(); j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).WithRequiredKeys(required)
+	j.Add("id", 1).AddObject("name").Add("tags", 1).Close()
+	if j.Close(); j.Err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(j.Err.Error(), "tags") || strings.Contains(j.Err.Error(), "name") {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+}
+
+func TestDetectDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		fn  func(*Builder)
+		err bool
+	}{
+		{func(j *Builder) { j.Add("a", 1).Add("b", 2) }, false},
+		{func(j *Builder) { j.Add("a", 1).Add("a", 2) }, true},
+		{func(j *Builder) { j.AddAll("a", 1, "b", 2, "a", 3) }, true},
+		{func(j *Builder) { j.Add("a", 1).AddObject("a").Close() }, true},
+		{func(j *Builder) { j.Add("a", 1).AddList("a").Close() }, true},
+		{func(j *Builder) { j.Add("a", 1).AddObjectFunc("a", f) }, true},
+		{func(j *Builder) { j.AddListFunc("a", g).AddListFunc("a", g) }, true},
+		{func(j *Builder) { j.AddString("a", "x").AddInt("a", 1) }, true},
+		{func(j *Builder) { j.AddRaw("a", []byte("1")).AddNull("a") }, true},
+		// Nested objects have their own keys.
+		{func(j *Builder) {
+			j.Add("a", 1).AddObjectFunc("o", func(o *Builder) error {
+				o.Add("a", 2).AddObject("o").Add("a", 3).Close()
+				return nil
+			})
+		}, false},
+		{func(j *Builder) {
+			j.AddListFunc("l", func(l *ListBuilder) error {
+				l.AddObjectFunc(func(o *Builder) error { o.Add("a", 1); return nil })
+				l.AddObjectFunc(func(o *Builder) error { o.Add("a", 1); return nil })
+				return nil
+			})
+		}, false},
+		{func(j *Builder) {
+			j.AddObjectFunc("o", func(o *Builder) error {
+				o.Add("a", 2).Add("a", 3)
+				return nil
+			})
+		}, true},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).SetDetectDuplicateKeys(true)
+		test.fn(j)
+		j.Close()
+		if test.err && (j.Err == nil || !strings.Contains(j.Err.Error(), "Duplicate key")) {
+			t.Errorf("%d have <%v> want a duplicate key error", i, j.Err)
+		} else if !test.err && j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+	}
+
+	// Off by default.
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).Add("a", 1).Add("a", 2).Close()
+	if j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+	if got, want := buf.String(), `{"a":1,"a":2}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if j.keys != nil {
+		t.Error("keys tracked while off")
+	}
+}
+
+func TestKeyFunc(t *testing.T) {
+	snake := func(key string) string {
+		var b strings.Builder
+		for i, r := range key {
+			if 'A' <= r && r <= 'Z' {
+				if i > 0 {
+					b.WriteByte('_')
+				}
+				r += 'a' - 'A'
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetKeyFunc(snake).Add("userID", "fooBar")
+	j.AddObject("homeAddress").Add("streetName", "mainSt").Close()
+	l := j.AddList("pastOrders")
+	l.AddObject().AddAll("orderID", 1, "itemCount", 2).Close()
+	l.Close()
+	j.AddObjectFunc("lastLogin", func(b *Builder) error {
+		b.AddListFunc("ipAddrs", g)
+		return nil
+	}).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"user_i_d":"fooBar","home_address":{"street_name":"mainSt"},` +
+		`"past_orders":[{"order_i_d":1,"item_count":2}],"last_login":{"ip_addrs":[1,2,3]}}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// Duplicates are detected after the transformation.
+	buf.Reset()
+	j = NewBuilder(&buf).SetKeyFunc(strings.ToLower).SetDetectDuplicateKeys(true)
+	if j.Add("a", 1).Add("A", 2).Err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestValidateKeys(t *testing.T) {
+	tests := []struct {
+		key                   string
+		validate, rejectEmpty bool
+		ok                    bool
+	}{
+		{"caf\xc3\xa9", true, true, true},
+		{"日本", true, true, true},
+		{"caf\xc3", true, false, false},
+		{"\xff", true, false, false},
+		{"\xff", false, false, true},
+		{"", true, false, true},
+		{"", true, true, false},
+		{"", false, true, false},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).SetValidateKeys(test.validate).SetRejectEmptyKeys(test.rejectEmpty)
+		j.AddObject("o").Add(test.key, 1).Close()
+		if j.Close(); (j.Err == nil) != test.ok {
+			t.Errorf("%d: key %q have <%v> want ok=%t", i, test.key, j.Err, test.ok)
+		}
+		if !test.ok && strings.Contains(buf.String(), `:1`) {
+			t.Errorf("%d: key %q was written <%s>", i, test.key, buf.String())
+		}
+	}
+}
+
+func TestAddBytesKey(t *testing.T) {
+	for i, key := range append(addStringTests, "<k>", "a b") {
+		for _, escapeHTML := range []bool{true, false} {
+			var want, got bytes.Buffer
+			NewBuilder(&want).SetEscapeHTML(escapeHTML).Add("a", 1).Add(key, key).Close()
+			j := NewBuilder(&got).SetEscapeHTML(escapeHTML).Add("a", 1).AddBytesKey([]byte(key), key).Close()
+			if j.Err != nil {
+				t.Fatal(j.Err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+			}
+		}
+	}
+
+	// Key checks still apply.
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetKeyFunc(strings.ToUpper).SetDetectDuplicateKeys(true)
+	j.AddBytesKey([]byte("a"), 1).AddBytesKey([]byte("b"), 2).Close()
+	if got, want := buf.String(), `{"A":1,"B":2}`; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+	buf.Reset()
+	j = NewBuilder(&buf).SetDetectDuplicateKeys(true).AddBytesKey([]byte("a"), 1).Add("a", 2)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func BenchmarkAddBytesKey(b *testing.B) {
+	keys := make([][]byte, benchLoad)
+	for i := range keys {
+		keys[i] = []byte("key " + strconv.Itoa(i))
+	}
+	for _, typed := range []bool{false, true} {
+		name := "Add"
+		if typed {
+			name = "AddBytesKey"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				j := NewBuilder(&buf)
+				for _, k := range keys {
+					if typed {
+						j.AddBytesKey(k, true)
+					} else {
+						j.Add(string(k), true)
+					}
+				}
+				if j.Close(); j.Err != nil {
+					b.Fatal(j.Err)
+				}
+				b.SetBytes(int64(buf.Len()))
+				buf.Reset()
+			}
+		})
+	}
+}
+
+func TestKeyCache(t *testing.T) {
+	build := func(cache bool) string {
+		var buf bytes.Buffer
+		l := NewListBuilder(&buf).SetKeyCache(cache)
+		for i, key := range append(addStringTests, addStringTests...) {
+			if i == len(addStringTests) {
+				// A change in escaping isn't served from the cache.
+				l.SetEscapeHTML(false)
+			}
+			o := l.AddObject().Add(key, 1).AddBytesKey([]byte(key), 2)
+			o.AddObject("nested").AddString(key, "x").Close()
+			o.Close()
+		}
+		if l.Close(); l.Err != nil {
+			t.Fatal(l.Err)
+		}
+		return buf.String()
+	}
+	if uncached, cached := build(false), build(true); cached != uncached {
+		t.Errorf("have <%s> want <%s>", cached, uncached)
+	}
+}
+
+func BenchmarkKeyCache(b *testing.B) {
+	keys := []string{"id", "name", "email", "created_at", "is_active"}
+	for _, cache := range []bool{false, true} {
+		name := "Uncached"
+		if cache {
+			name = "Cached"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				l := NewListBuilder(&buf).SetKeyCache(cache)
+				for row := 0; row < benchLoad; row++ {
+					o := l.AddObject()
+					for _, key := range keys {
+						o.AddInt(key, row)
+					}
+					o.Close()
+				}
+				if l.Close(); l.Err != nil {
+					b.Fatal(l.Err)
+				}
+				b.SetBytes(int64(buf.Len()))
+				buf.Reset()
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"errors"
+)
+
+// errTruncated is returned internally by ListBuilder.preadd when an element is
+// dropped by WithMaxElements. It is never stored in Err.
+var errTruncated = errors.New("ListBuilder truncated")
+
+type elementLimit struct {
+	max       int
+	marker    interface{}
+	truncated bool
+}
+
+// WithMaxElements limits this list to n elements. Once n elements have been
+// added, further elements are silently dropped (builders returned for dropped
+// elements write nothing) and Truncated reports true.
+func (b *ListBuilder) WithMaxElements(n int) *ListBuilder {
+	if b.limit == nil {
+		b.limit = &elementLimit{}
+	}
+	b.limit.max = n
+	return b
+}
+
+// WithTruncationMarker sets a value, like map[string]bool{"truncated": true},
+// that is appended as the final element when WithMaxElements dropped anything.
+func (b *ListBuilder) WithTruncationMarker(marker interface{}) *ListBuilder {
+	if b.limit == nil {
+		b.limit = &elementLimit{max: int(^uint(0) >> 1)}
+	}
+	b.limit.marker = marker
+	return b
+}
+
+// Truncated returns whether any elements were dropped by WithMaxElements.
+func (b *ListBuilder) Truncated() bool {
+	return b.limit != nil && b.limit.truncated
+}
+
+var elementIndentBytes = []byte("\n  ")
+
+// WithNewlineSeparatedElements writes each element of this list on its own
+// line, indented by two spaces. Elements are still separated by commas, so the
+// output remains valid JSON; only whitespace is added. Values nested inside
+// the elements are unaffected.
+func (b *ListBuilder) WithNewlineSeparatedElements() *ListBuilder {
+	b.perLine = true
+	return b
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestMaxElements(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewListBuilder(&buf).WithMaxElements(3)
+	for i := 0; i < 10; i++ {
+		j.Add(i)
+	}
+	if !j.Truncated() {
+		t.Error("Expected truncation")
+	}
+	j.AddObject().Add("a", 1).Close()
+	j.AddListFunc(g)
+	if j.Close(); j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+	if got, want := buf.String(), `[0,1,2]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewListBuilder(&buf).WithMaxElements(3).WithTruncationMarker(map[string]bool{"truncated": true})
+	j.AddAll(0, 1, 2)
+	if j.Truncated() {
+		t.Error("Unexpected truncation")
+	}
+	j.Add(3).Close()
+	if got, want := buf.String(), `[0,1,2,{"truncated":true}]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewListBuilder(&buf).WithMaxElements(3).WithTruncationMarker("...")
+	j.AddAll(0, 1).Close()
+	if got, want := buf.String(), `[0,1]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestNewlineSeparatedElements(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewListBuilder(&buf).WithNewlineSeparatedElements()
+	j.Add(1).Add("two").AddListFunc(g).AddObjectFunc(f).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := "[\n  1,\n  \"two\",\n  [1,2,3],\n  {\"baz\":7}\n]"
+	if got := buf.String(); got != want {
+		t.Errorf("have <%q> want <%q>", got, want)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("invalid JSON <%s>", buf.String())
+	}
+
+	buf.Reset()
+	NewListBuilder(&buf).WithNewlineSeparatedElements().Close()
+	if got, want := buf.String(), `[]`; got != want {
+		t.Errorf("have <%q> want <%q>", got, want)
+	}
+}
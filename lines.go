@@ -0,0 +1,86 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"errors"
+	"io"
+)
+
+var newlineBytes = []byte{'\n'}
+
+// A LinesWriter writes newline-delimited JSON (also known as JSON Lines), one
+// record per line.
+type LinesWriter struct {
+	w         io.Writer
+	records   int
+	syncEvery int
+	sync      func() error
+	cur       builderCommon
+	Err       error
+}
+
+// NewLinesWriter returns a new LinesWriter that writes to w.
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return &LinesWriter{w: w}
+}
+
+// WithSyncEvery calls sync (e.g. an os.File's Sync) after every n records. An
+// error from sync is stored in Err and fails the record that triggered it.
+func (l *LinesWriter) WithSyncEvery(n int, sync func() error) *LinesWriter {
+	l.syncEvery, l.sync = n, sync
+	return l
+}
+
+// Object returns a builder for the next record, which is a JSON object. The
+// record's newline is written when the builder is closed.
+//
+// The previous record must have been closed, otherwise Err is set. If it
+// failed instead, its error is stored in Err, since it may have left a partial
+// line.
+func (l *LinesWriter) Object() *Builder {
+	b := NewBuilder(l.w)
+	b.Err = l.next(b)
+	b.afterClose = l.endRecord
+	return b
+}
+
+// List returns a builder for the next record, which is a JSON list. The
+// record's newline is written when the builder is closed.
+//
+// The previous record must have been closed, otherwise Err is set. If it
+// failed instead, its error is stored in Err, since it may have left a partial
+// line.
+func (l *LinesWriter) List() *ListBuilder {
+	b := NewListBuilder(l.w)
+	b.Err = l.next(b)
+	b.afterClose = l.endRecord
+	return b
+}
+
+// next starts the record written by b.
+func (l *LinesWriter) next(b builderCommon) error {
+	if l.Err == nil && l.cur != nil {
+		if err := l.cur.err(); err != nil {
+			l.Err = err
+		} else if !l.cur.closed() {
+			l.Err = errors.New("LinesWriter record started before the previous one was closed")
+		}
+	}
+	l.cur = b
+	return l.Err
+}
+
+func (l *LinesWriter) endRecord() error {
+	if l.Err != nil {
+		return l.Err
+	}
+	if _, l.Err = l.w.Write(newlineBytes); l.Err != nil {
+		return l.Err
+	}
+	l.records++
+	if l.syncEvery > 0 && l.records%l.syncEvery == 0 {
+		l.Err = l.sync()
+	}
+	return l.Err
+}
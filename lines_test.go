@@ -0,0 +1,90 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestLinesWriterSyncEvery(t *testing.T) {
+	var buf bytes.Buffer
+	syncs := 0
+	l := NewLinesWriter(&buf).WithSyncEvery(3, func() error {
+		syncs++
+		return nil
+	})
+	for i := 1; i <= 7; i++ {
+		if err := l.Object().Add("i", i).Close().Err; err != nil {
+			t.Fatal(err)
+		}
+		if want := i / 3; syncs != want {
+			t.Errorf("after %d records have %d syncs want %d", i, syncs, want)
+		}
+	}
+
+	buf.Reset()
+	l = NewLinesWriter(&buf).WithSyncEvery(1, func() error { return errors.New("sync failed") })
+	if l.Object().Add("i", 1).Close().Err == nil {
+		t.Error("Expected error")
+	}
+	if l.Err == nil {
+		t.Error("Expected error")
+	}
+	if l.Object().Add("i", 2).Close().Err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), "{\"i\":1}\n"; got != want {
+		t.Errorf("have <%q> want <%q>", got, want)
+	}
+}
+
+func TestLinesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLinesWriter(&buf)
+	l.Object().Add("a", 1).Close()
+	l.List().Add(2).AddObjectFunc(f).Close()
+	r := l.Object()
+	r.AddObject("o").Add("b", "c").Close()
+	r.Close()
+	if l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	want := "{\"a\":1}\n[2,{\"baz\":7}]\n{\"o\":{\"b\":\"c\"}}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("have <%q> want <%q>", got, want)
+	}
+
+	// A record can't be started before the previous one is closed.
+	buf.Reset()
+	l = NewLinesWriter(&buf)
+	l.Object().Add("a", 1).Close()
+	first := l.Object().Add("b", 2)
+	if second := l.List(); second.Err == nil {
+		t.Error("Expected error")
+	}
+	if l.Err == nil {
+		t.Error("Expected error")
+	}
+	if l.Object().Add("c", 3).Close().Err == nil {
+		t.Error("Expected error")
+	}
+	if first.Close().Err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), "{\"a\":1}\n{\"b\":2}"; got != want {
+		t.Errorf("have <%q> want <%q>", got, want)
+	}
+
+	// A record that failed passes its error on, rather than a sequencing error.
+	buf.Reset()
+	l = NewLinesWriter(&buf)
+	failed := l.Object().Add("x", make(chan int)).Close()
+	if failed.Err == nil {
+		t.Fatal("Expected error")
+	}
+	if l.Object(); l.Err != failed.Err {
+		t.Errorf("have <%v> want <%v>", l.Err, failed.Err)
+	}
+}
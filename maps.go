@@ -0,0 +1,147 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// AddMap emits m as a JSON object value with the given key, writing each entry
+// directly instead of marshaling the whole map.
+//
+// m must be a map[string]interface{}, map[int]interface{}, or
+// map[int64]interface{}. Entries are emitted in sorted key order. Integer keys
+// are quoted, like the stdlib does, but sorted by numeric value.
+func (b *Builder) AddMap(key string, m interface{}) *Builder {
+	if b.done() {
+		return b
+	}
+	switch m := m.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(m, nil)
+		return b.AddObjectFunc(key, func(b *Builder) error {
+			for _, k := range keys {
+				b.Add(k, m[k])
+			}
+			return nil
+		})
+	case map[int]interface{}:
+		keys := make([]int, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		return b.AddObjectFunc(key, func(b *Builder) error {
+			for _, k := range keys {
+				b.Add(strconv.Itoa(k), m[k])
+			}
+			return nil
+		})
+	case map[int64]interface{}:
+		keys := make([]int64, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		return b.AddObjectFunc(key, func(b *Builder) error {
+			for _, k := range keys {
+				b.Add(strconv.FormatInt(k, 10), m[k])
+			}
+			return nil
+		})
+	}
+	if b.Err == nil {
+		b.Err = fmt.Errorf("AddMap takes a map[string]interface{}, map[int]interface{}, or map[int64]interface{} but got %T", m)
+	}
+	return b
+}
+
+// AddMapSorted emits each entry of m as a key value pair of this object (not
+// nested under a key, unlike AddMap), in sorted key order.
+func (b *Builder) AddMapSorted(m map[string]interface{}) *Builder {
+	for _, k := range sortedKeys(m, nil) {
+		b.Add(k, m[k])
+	}
+	return b
+}
+
+// AddRows emits rows as a JSON list value with the given key, with every row's
+// keys in the same order. See ListBuilder.AddRows.
+func (b *Builder) AddRows(key string, rows []map[string]interface{}, order []string) *Builder {
+	return b.AddListFunc(key, func(b *ListBuilder) error {
+		b.AddRows(rows, order)
+		return nil
+	})
+}
+
+// AddRows emits each of rows as an object element, with every row's keys in the
+// same order, which makes the output more compressible.
+//
+// The order is given by order or, if it's nil, the sorted keys of the first
+// row. A row's keys that aren't in the order are emitted after the others in
+// sorted order, and keys that a row doesn't have are skipped.
+func (b *ListBuilder) AddRows(rows []map[string]interface{}, order []string) *ListBuilder {
+	if order == nil && len(rows) > 0 {
+		order = sortedKeys(rows[0], nil)
+	}
+	inOrder := make(map[string]struct{}, len(order))
+	for _, k := range order {
+		inOrder[k] = struct{}{}
+	}
+
+	for _, row := range rows {
+		b.AddObjectFunc(func(b *Builder) error {
+			n := 0
+			for _, k := range order {
+				if v, ok := row[k]; ok {
+					b.Add(k, v)
+					n++
+				}
+			}
+			if n < len(row) {
+				for _, k := range sortedKeys(row, inOrder) {
+					b.Add(k, row[k])
+				}
+			}
+			return nil
+		})
+	}
+	return b
+}
+
+// sortedKeys returns the keys of m, except those in skip, in sorted order.
+func sortedKeys(m map[string]interface{}, skip map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if _, ok := skip[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AddSparseArray emits a JSON list value of length maxIndex+1 with the given
+// key, with each of values at its index and null everywhere else. An index
+// outside [0, maxIndex] sets Err.
+func (b *Builder) AddSparseArray(key string, maxIndex int, values map[int]interface{}) *Builder {
+	if b.done() {
+		return b
+	}
+	for i := range values {
+		if i < 0 || i > maxIndex {
+			if b.Err == nil {
+				b.Err = fmt.Errorf("AddSparseArray index %d is outside [0,%d]", i, maxIndex)
+			}
+			return b
+		}
+	}
+	return b.AddListFunc(key, func(b *ListBuilder) error {
+		for i := 0; i <= maxIndex; i++ {
+			b.Add(values[i])
+		}
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var addMapTests = []struct {
+	out string
+	m   interface{}
+}{
+	{`{"m":{}}`, map[string]interface{}{}},
+	{`{"m":{"a":1,"b":[2],"c":"3"}}`, map[string]interface{}{"c": "3", "a": 1, "b": []int{2}}},
+	{`{"m":{"-1":"x","2":"y","10":"z"}}`, map[int]interface{}{10: "z", -1: "x", 2: "y"}},
+	{`{"m":{"-9223372036854775808":1,"3":2,"9223372036854775807":3}}`, map[int64]interface{}{
+		9223372036854775807: 3, -9223372036854775808: 1, 3: 2,
+	}},
+}
+
+func TestAddMap(t *testing.T) {
+	for i, test := range addMapTests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).AddMap("m", test.m).Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+
+		// The stdlib sorts integer keys as strings, so only compare contents.
+		stdlib, err := json.Marshal(map[string]interface{}{"m": test.m})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var have, want interface{}
+		if err := json.Unmarshal(buf.Bytes(), &have); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(stdlib, &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("%d have <%s> want <%s>", i, buf.String(), stdlib)
+		}
+	}
+
+	var buf bytes.Buffer
+	if j := NewBuilder(&buf).AddMap("m", map[bool]interface{}{}); j.Err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestAddMapSorted(t *testing.T) {
+	m := map[string]interface{}{"zeta": 1, "alpha": []int{2}, "mu": "3", "beta": nil, "": true}
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).Add("first", 0).AddMapSorted(m).AddMapSorted(nil).Add("last", 4).Close()
+		if j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		want := `{"first":0,"":true,"alpha":[2],"beta":null,"mu":"3","zeta":1,"last":4}`
+		if got := buf.String(); got != want {
+			t.Errorf("have <%s> want <%s>", got, want)
+		}
+	}
+}
+
+func TestAddRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "a", "id": 1, "ok": true},
+		{"ok": false, "id": 2, "name": "b"},
+		{"id": 3, "name": "c", "extra": 0},
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddRows("rows", rows, nil).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"rows":[{"id":1,"name":"a","ok":true},{"id":2,"name":"b","ok":false},{"id":3,"name":"c","extra":0}]}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	l := NewListBuilder(&buf).AddRows(rows[:2], []string{"ok", "name", "id"}).Close()
+	if l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	want = `[{"ok":true,"name":"a","id":1},{"ok":false,"name":"b","id":2}]`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestAddSparseArray(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddSparseArray("a", 3, map[int]interface{}{0: "x", 3: 7}).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":["x",null,null,7]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	for _, i := range []int{-1, 4} {
+		buf.Reset()
+		if j := NewBuilder(&buf).AddSparseArray("a", 3, map[int]interface{}{i: 1}); j.Err == nil {
+			t.Errorf("%d Expected error", i)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%d have <%s> want nothing written", i, buf.String())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// Must panics with Err if it's set and otherwise returns b. It's meant for the
+// end of a chain whose failure would be a programming error, like one writing
+// to a bytes.Buffer.
+func (b *Builder) Must() *Builder {
+	if b.Err != nil {
+		panic(b.Err)
+	}
+	return b
+}
+
+// Must panics with Err if it's set and otherwise returns b. It's meant for the
+// end of a chain whose failure would be a programming error, like one writing
+// to a bytes.Buffer.
+func (b *ListBuilder) Must() *ListBuilder {
+	if b.Err != nil {
+		panic(b.Err)
+	}
+	return b
+}
+
+// Must is b.Must(), for wrapping an expression that returns a Builder, like
+// json.Must(NewBufferBuilder().Add("a", 1).Close()).
+func Must(b *Builder) *Builder {
+	return b.Must()
+}
@@ -0,0 +1,43 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMust(t *testing.T) {
+	var buf bytes.Buffer
+	j := Must(NewBuilder(&buf).Add("a", 1).AddListFunc("l", g).Close())
+	if got, want := buf.String(), `{"a":1,"l":[1,2,3]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	j.Must()
+	NewListBuilder(&buf).Add(1).Must().Close().Must()
+
+	for i, fn := range []func() (error, func()){
+		func() (error, func()) {
+			j := NewBuilder(&failingWriter{failAt: 1}).Add("a", 1)
+			return j.Err, func() { Must(j) }
+		},
+		func() (error, func()) {
+			j := NewBuilder(&failingWriter{failAt: 2}).Add("a", 1).Close()
+			return j.Err, func() { j.Must() }
+		},
+		func() (error, func()) {
+			l := NewListBuilder(&buf).AddObject().Add("a", func() {})
+			return l.Err, func() { l.Must() }
+		},
+	} {
+		err, must := fn()
+		func() {
+			defer func() {
+				if r := recover(); r == nil || r != err {
+					t.Errorf("%d: have <%v> want a panic with <%v>", i, r, err)
+				}
+			}()
+			must()
+		}()
+	}
+}
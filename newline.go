@@ -0,0 +1,20 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// SetTrailingNewline sets whether a newline is written after the document,
+// when the top-level builder is closed, as many tools expect of a document in
+// a file or pipe. It's written after everything else, including the output of
+// WithFinalizer.
+func (b *Builder) SetTrailingNewline(on bool) *Builder {
+	b.s.trailingNewline = on
+	return b
+}
+
+// SetTrailingNewline sets whether a newline is written after the document,
+// when the top-level builder is closed, as many tools expect of a document in
+// a file or pipe.
+func (b *ListBuilder) SetTrailingNewline(on bool) *ListBuilder {
+	b.s.trailingNewline = on
+	return b
+}
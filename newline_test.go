@@ -0,0 +1,52 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTrailingNewline(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(w *bytes.Buffer) error
+	}{
+		{`{"a":1,"o":{"b":[1,2,3]},"l":[{}]}` + "\n", func(w *bytes.Buffer) error {
+			j := NewBuilder(w).SetTrailingNewline(true).Add("a", 1)
+			j.AddObjectFunc("o", func(b *Builder) error {
+				b.AddListFunc("b", g)
+				return nil
+			})
+			l := j.AddList("l")
+			l.AddObject().Close()
+			l.Close()
+			return j.Close().Err
+		}},
+		{"[[],{}]\n", func(w *bytes.Buffer) error {
+			l := NewListBuilder(w).SetTrailingNewline(true)
+			l.AddList().Close()
+			l.AddObject().Close()
+			return l.Close().Err
+		}},
+		{"{}\n", func(w *bytes.Buffer) error {
+			return NewBuilder(w).SetTrailingNewline(true).Close().Err
+		}},
+		{`{"a":1}`, func(w *bytes.Buffer) error {
+			return NewBuilder(w).SetTrailingNewline(false).Add("a", 1).Close().Err
+		}},
+		{`{"a":1,"b":2}` + "\n", func(w *bytes.Buffer) error {
+			return NewBuilder(w).SetTrailingNewline(true).SetCanonical(true).Add("b", 2).Add("a", 1).Close().Err
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		if err := test.fn(&buf); err != nil {
+			t.Errorf("%d: Unexpected error <%s>", i, err)
+			continue
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d: have <%q> want <%q>", i, got, test.out)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"reflect"
+)
+
+// WithNullSentinel emits raw, verbatim, everywhere the document would otherwise
+// have a null: nil values (including nil pointers, maps, slices, and
+// interfaces) and helpers that emit null, like AddTriState.
+//
+// This is non-standard and only for legacy consumers that expect something like
+// "" instead of null. raw is not validated. Only values passed directly to the
+// builder are affected.
+func (b *Builder) WithNullSentinel(raw []byte) *Builder {
+	b.s.nullSentinel = verbatim(raw)
+	return b
+}
+
+// WithNullSentinel emits raw, verbatim, everywhere the document would otherwise
+// have a null: nil values (including nil pointers, maps, slices, and
+// interfaces) and helpers that emit null, like AddTriState.
+//
+// This is non-standard and only for legacy consumers that expect something like
+// "" instead of null. raw is not validated. Only values passed directly to the
+// builder are affected.
+func (b *ListBuilder) WithNullSentinel(raw []byte) *ListBuilder {
+	b.s.nullSentinel = verbatim(raw)
+	return b
+}
+
+// null returns what to emit for a null.
+func (s *stream) null() []byte {
+	if s.nullSentinel != nil {
+		return s.nullSentinel
+	}
+	return nullBytes
+}
+
+// AddNull emits a single key value pair with a null value (or the sentinel
+// from WithNullSentinel).
+func (b *Builder) AddNull(key string) *Builder {
+	return b.addRaw(key, b.s.null())
+}
+
+// AddNull emits a single null value (or the sentinel from WithNullSentinel).
+func (b *ListBuilder) AddNull() *ListBuilder {
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.write(b.s.null())
+	b.endElement()
+	return b
+}
+
+// isNil returns whether v is nil or a nil pointer, map, slice, or interface,
+// all of which the stdlib encodes as null.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNullSentinel(t *testing.T) {
+	var nilPtr *int
+	var nilMap map[string]int
+	fn := func(j *Builder) {
+		j.Add("a", nil).Add("b", nilPtr).Add("c", nilMap).Add("d", 0).AddTriState("e", 0)
+		j.AddNull("f")
+		j.AddList("l").Add(nil).Add("").AddNull().Close()
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithNullSentinel([]byte(`""`))
+	fn(j)
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":"","b":"","c":"","d":0,"e":"","f":"","l":["","",""]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf)
+	fn(j)
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":null,"b":null,"c":null,"d":0,"e":null,"f":null,"l":[null,"",null]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
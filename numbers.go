@@ -0,0 +1,223 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// AddInt emits a single key value pair with an integer value. The output is the
+// same as Add(key, value), but it's formatted directly into the output.
+func (b *Builder) AddInt(key string, value int) *Builder {
+	return b.AddInt64(key, int64(value))
+}
+
+// AddInt64 emits a single key value pair with an integer value. The output is
+// the same as Add(key, value), but it's formatted directly into the output.
+func (b *Builder) AddInt64(key string, value int64) *Builder {
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = strconv.AppendInt(b.s.buf[:0], value, 10)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddInt emits a single integer value. The output is the same as Add(value),
+// but it's formatted directly into the output.
+func (b *ListBuilder) AddInt(value int) *ListBuilder {
+	return b.AddInt64(int64(value))
+}
+
+// AddInt64 emits a single integer value. The output is the same as Add(value),
+// but it's formatted directly into the output.
+func (b *ListBuilder) AddInt64(value int64) *ListBuilder {
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = strconv.AppendInt(b.s.buf[:0], value, 10)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+// AddFloat64 emits a single key value pair with a float value. The output is
+// the same as Add(key, value), the shortest representation that round trips,
+// but it's formatted directly into the output. Like Add, NaN and infinite
+// values set Err (unless WithPythonNonFinite is used).
+func (b *Builder) AddFloat64(key string, value float64) *Builder {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return b.Add(key, value)
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = b.s.appendFloat64(b.s.buf[:0], value)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddFloat64Prec emits a single key value pair with a float value formatted
+// with exactly prec digits after the decimal point. NaN and infinite values set
+// Err (unless WithPythonNonFinite is used).
+func (b *Builder) AddFloat64Prec(key string, value float64, prec int) *Builder {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return b.Add(key, value)
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = strconv.AppendFloat(b.s.buf[:0], value, 'f', prec, 64)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddFloat64 emits a single float value. The output is the same as Add(value),
+// the shortest representation that round trips, but it's formatted directly
+// into the output. Like Add, NaN and infinite values set Err (unless
+// WithPythonNonFinite is used).
+func (b *ListBuilder) AddFloat64(value float64) *ListBuilder {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return b.Add(value)
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = b.s.appendFloat64(b.s.buf[:0], value)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+// AddFloat64Prec emits a single float value formatted with exactly prec digits
+// after the decimal point. NaN and infinite values set Err (unless
+// WithPythonNonFinite is used).
+func (b *ListBuilder) AddFloat64Prec(value float64, prec int) *ListBuilder {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return b.Add(value)
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = strconv.AppendFloat(b.s.buf[:0], value, 'f', prec, 64)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+// appendFloat64 appends the finite f formatted like encoding/json does, or like
+// JavaScript does with WithJSNumberFormat.
+func (s *stream) appendFloat64(dst []byte, f float64) []byte {
+	if s.jsNumbers {
+		return append(dst, jsNumber(f)...)
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9, like encoding/json.
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
+// AddBigInt emits a single key value pair with an arbitrary precision integer
+// value, as an unquoted JSON number with every digit. A nil v is emitted as
+// null.
+func (b *Builder) AddBigInt(key string, v *big.Int) *Builder {
+	if v == nil {
+		return b.AddNull(key)
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = v.Append(b.s.buf[:0], 10)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddBigInt emits a single arbitrary precision integer value, as an unquoted
+// JSON number with every digit. A nil v is emitted as null.
+func (b *ListBuilder) AddBigInt(v *big.Int) *ListBuilder {
+	if v == nil {
+		return b.AddNull()
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = v.Append(b.s.buf[:0], 10)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+// AddBigFloat emits a single key value pair with an arbitrary precision float
+// value, as an unquoted JSON number formatted like v.Text('g', prec): prec is
+// the number of significant digits, or -1 for the fewest that uniquely
+// identify v. A nil v is emitted as null. An infinite v, which isn't a valid
+// JSON number, sets Err.
+func (b *Builder) AddBigFloat(key string, v *big.Float, prec int) *Builder {
+	if v == nil {
+		return b.AddNull(key)
+	}
+	if b.done() {
+		return b
+	}
+	if b.Err = checkBigFloat(v); b.Err != nil {
+		return b
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = v.Append(b.s.buf[:0], 'g', prec)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddBigFloat emits a single arbitrary precision float value, as an unquoted
+// JSON number formatted like v.Text('g', prec): prec is the number of
+// significant digits, or -1 for the fewest that uniquely identify v. A nil v is
+// emitted as null. An infinite v, which isn't a valid JSON number, sets Err.
+func (b *ListBuilder) AddBigFloat(v *big.Float, prec int) *ListBuilder {
+	if v == nil {
+		return b.AddNull()
+	}
+	if b.done() {
+		return b
+	}
+	if b.Err = checkBigFloat(v); b.Err != nil {
+		return b
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = v.Append(b.s.buf[:0], 'g', prec)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+func checkBigFloat(v *big.Float) error {
+	if v.IsInf() {
+		return fmt.Errorf("Unsupported value: %s", v)
+	}
+	return nil
+}
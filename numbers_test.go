@@ -0,0 +1,148 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+func TestAddInt(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 42, -9000, math.MaxInt32, math.MinInt64, math.MaxInt64} {
+		var want, got bytes.Buffer
+		NewBuilder(&want).Add("a", v).Add("b", int(v)).Close()
+		j := NewBuilder(&got).AddInt64("a", v).AddInt("b", int(v)).Close()
+		if j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("have <%s> want <%s>", got.String(), want.String())
+		}
+
+		want.Reset()
+		got.Reset()
+		NewListBuilder(&want).Add(v).Add(int(v)).Close()
+		l := NewListBuilder(&got).AddInt64(v).AddInt(int(v)).Close()
+		if l.Err != nil {
+			t.Fatal(l.Err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("have <%s> want <%s>", got.String(), want.String())
+		}
+	}
+}
+
+func TestAddFloat64(t *testing.T) {
+	values := []float64{
+		0, math.Copysign(0, -1), 0.1, -6.2, 1, 100, 1e20, 1e21, 1.5e300, 1e-6, 1e-7, 1.5e-9,
+		math.MaxFloat64, math.SmallestNonzeroFloat64, math.Pi,
+	}
+	for _, v := range values {
+		var want, got bytes.Buffer
+		NewBuilder(&want).Add("a", v).Close()
+		j := NewBuilder(&got).AddFloat64("a", v).Close()
+		if j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("have <%s> want <%s>", got.String(), want.String())
+		}
+
+		want.Reset()
+		got.Reset()
+		NewListBuilder(&want).Add(v).Close()
+		l := NewListBuilder(&got).AddFloat64(v).Close()
+		if l.Err != nil {
+			t.Fatal(l.Err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("have <%s> want <%s>", got.String(), want.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddFloat64Prec("a", 0.1, 3).AddFloat64Prec("b", -2.5, 0)
+	j.AddList("l").AddFloat64Prec(1e21, 1).Close()
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":0.100,"b":-2,"l":[1000000000000000000000.0]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		buf.Reset()
+		if err := NewBuilder(&buf).AddFloat64("a", v).Err; err == nil {
+			t.Errorf("%v: expected error", v)
+		}
+		if err := NewBuilder(&buf).AddFloat64Prec("a", v, 2).Err; err == nil {
+			t.Errorf("%v: expected error", v)
+		}
+		if err := NewListBuilder(&buf).AddFloat64(v).Err; err == nil {
+			t.Errorf("%v: expected error", v)
+		}
+		if err := NewListBuilder(&buf).AddFloat64Prec(v, 2).Err; err == nil {
+			t.Errorf("%v: expected error", v)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%v: have <%s> want nothing written", v, buf.String())
+		}
+	}
+}
+
+func TestAddBig(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890123456789", 10)
+	pi, _ := new(big.Float).SetPrec(200).SetString("3.14159265358979323846264338327950288419716939937510")
+	tiny, _ := new(big.Float).SetPrec(100).SetString("1.000000000000000000000001e-400")
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddBigInt("i", huge).AddBigInt("z", new(big.Int)).AddBigInt("n", nil)
+	j.AddBigFloat("pi", pi, 40).AddBigFloat("tiny", tiny, -1).AddBigFloat("f", big.NewFloat(2.5), -1)
+	j.AddListFunc("l", func(l *ListBuilder) error {
+		l.AddBigInt(huge).AddBigInt(nil).AddBigFloat(pi, 5).AddBigFloat(nil, 5)
+		return nil
+	}).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"i":-123456789012345678901234567890123456789,"z":0,"n":null,` +
+		`"pi":3.141592653589793238462643383279502884197,"tiny":1.000000000000000000000001e-400,"f":2.5,` +
+		`"l":[-123456789012345678901234567890123456789,null,3.1416,null]}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	var v interface{}
+	if err := json.Unmarshal(buf.Bytes(), &v); err != nil {
+		t.Errorf("Unexpected error <%s>", err)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).Add("a", 1).AddBigFloat("inf", new(big.Float).SetInf(false), -1)
+	if got, want := buf.String(), `{"a":1`; got != want || j.Err == nil {
+		t.Errorf("have <%s> <%v> want <%s> and an error", got, j.Err, want)
+	}
+	if l := NewListBuilder(&buf).AddBigFloat(new(big.Float).SetInf(true), -1); l.Err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func BenchmarkBuilderAddInt(b *testing.B) {
+	b.ReportAllocs()
+	var buf bytes.Buffer
+	for i := 0; i < b.N; i++ {
+		j := NewBuilder(&buf)
+		for i := 0; i < benchLoad; i++ {
+			j.AddInt(strconv.Itoa(i), i)
+		}
+		j.Close()
+		if j.Err != nil {
+			b.Fatal(j.Err)
+		}
+		b.SetBytes(int64(len(buf.Bytes())))
+		buf.Reset()
+	}
+}
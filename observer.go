@@ -0,0 +1,19 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+// WithWriteObserver calls f with the bytes of each write to the underlying
+// writer, right after it happens. This is mostly useful in tests asserting
+// that output is streamed incrementally. f must not retain the chunk.
+func (b *Builder) WithWriteObserver(f func(chunk []byte)) *Builder {
+	b.s.observer = f
+	return b
+}
+
+// WithWriteObserver calls f with the bytes of each write to the underlying
+// writer, right after it happens. This is mostly useful in tests asserting
+// that output is streamed incrementally. f must not retain the chunk.
+func (b *ListBuilder) WithWriteObserver(f func(chunk []byte)) *ListBuilder {
+	b.s.observer = f
+	return b
+}
@@ -0,0 +1,39 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestWriteObserver(t *testing.T) {
+	var observed bytes.Buffer
+	chunks := 0
+	j := NewListBuilder(ioutil.Discard).WithWriteObserver(func(chunk []byte) {
+		chunks++
+		observed.Write(chunk)
+	})
+
+	prev := chunks
+	for i := 0; i < 5; i++ {
+		j.Add(i)
+		if chunks <= prev {
+			t.Errorf("%d element was not written before the next was added", i)
+		}
+		prev = chunks
+	}
+	j.Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	// Opening bracket, value, then a comma and value per subsequent element,
+	// then the closing bracket.
+	if chunks != 11 {
+		t.Errorf("have %d chunks want 11", chunks)
+	}
+	if got, want := observed.String(), `[0,1,2,3,4]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"reflect"
+)
+
+// AddIf emits a single key value pair, like Add, but only if cond is true.
+// Otherwise, it does nothing.
+func (b *Builder) AddIf(cond bool, key string, value interface{}) *Builder {
+	if !cond {
+		return b
+	}
+	return b.Add(key, value)
+}
+
+// AddIf emits a single value, like Add, but only if cond is true. Otherwise, it
+// does nothing.
+func (b *ListBuilder) AddIf(cond bool, value interface{}) *ListBuilder {
+	if !cond {
+		return b
+	}
+	return b.Add(value)
+}
+
+// AddOmitEmpty emits a single key value pair, like Add, unless value is empty by
+// the same rules as the omitempty struct tag: false, 0, a nil pointer or
+// interface, and an empty string, array, slice, or map. Structs are never
+// empty.
+func (b *Builder) AddOmitEmpty(key string, value interface{}) *Builder {
+	if isEmptyValue(value) {
+		return b
+	}
+	return b.Add(key, value)
+}
+
+// isEmptyValue matches encoding/json's definition of empty for omitempty.
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var optionalTests = []struct {
+	out string
+	fn  func(*Builder)
+}{
+	{`{}`, func(j *Builder) { j.AddIf(false, "a", 1) }},
+	{`{"a":1}`, func(j *Builder) { j.AddIf(true, "a", 1) }},
+	{`{"b":2}`, func(j *Builder) { j.AddIf(false, "a", 1).Add("b", 2) }},
+	{`{"a":1,"c":3}`, func(j *Builder) { j.Add("a", 1).AddIf(false, "b", 2).AddIf(true, "c", 3) }},
+	{`{"a":1}`, func(j *Builder) { j.Add("a", 1).AddIf(false, "b", 2) }},
+	{`{"l":[2]}`, func(j *Builder) { j.AddList("l").AddIf(false, 1).AddIf(true, 2).AddIf(false, 3).Close() }},
+
+	{`{}`, func(j *Builder) {
+		var p *int
+		var e error
+		j.AddOmitEmpty("nil", nil).AddOmitEmpty("s", "").AddOmitEmpty("i", 0).AddOmitEmpty("u", uint8(0))
+		j.AddOmitEmpty("f", 0.0).AddOmitEmpty("b", false).AddOmitEmpty("p", p).AddOmitEmpty("e", e)
+		j.AddOmitEmpty("sl", []int(nil)).AddOmitEmpty("sl2", []int{}).AddOmitEmpty("m", map[string]int{})
+		j.AddOmitEmpty("m2", map[string]int(nil)).AddOmitEmpty("a", [0]int{})
+	}},
+	{`{"s":"x","i":-1,"f":0.5,"b":true,"p":0,"sl":[0],"m":{"":0},"a":[0]}`, func(j *Builder) {
+		zero := 0
+		j.AddOmitEmpty("s", "x").AddOmitEmpty("i", -1).AddOmitEmpty("f", 0.5).AddOmitEmpty("b", true)
+		j.AddOmitEmpty("p", &zero).AddOmitEmpty("sl", []int{0}).AddOmitEmpty("m", map[string]int{"": 0})
+		j.AddOmitEmpty("a", [1]int{})
+	}},
+	// Like omitempty, a zero struct isn't empty.
+	{`{"z":{"A":0}}`, func(j *Builder) { j.AddOmitEmpty("z", struct{ A int }{}) }},
+}
+
+func TestOptional(t *testing.T) {
+	for i, test := range optionalTests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+		if !json.Valid(buf.Bytes()) {
+			t.Errorf("%d invalid JSON <%s>", i, buf.String())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"io"
+)
+
+type pageKeys struct {
+	items, page, total string
+}
+
+// A PageOption configures Paginated.
+type PageOption func(*pageKeys)
+
+// PageItemsKey sets the key of the items list, which defaults to "items".
+func PageItemsKey(key string) PageOption {
+	return func(k *pageKeys) { k.items = key }
+}
+
+// PageNumberKey sets the key of the page number, which defaults to "page".
+func PageNumberKey(key string) PageOption {
+	return func(k *pageKeys) { k.page = key }
+}
+
+// PageTotalKey sets the key of the total count, which defaults to "total".
+func PageTotalKey(key string) PageOption {
+	return func(k *pageKeys) { k.total = key }
+}
+
+// Paginated writes a paginated list envelope to w, like
+// {"items":[...],"page":1,"total":3}, with the items streamed from the list
+// computed by items.
+func Paginated(w io.Writer, page, total int, items ListBuilderFunc, opts ...PageOption) error {
+	keys := pageKeys{"items", "page", "total"}
+	for _, opt := range opts {
+		opt(&keys)
+	}
+
+	return NewBuilder(w).
+		AddListFunc(keys.items, items).
+		Add(keys.page, page).
+		Add(keys.total, total).
+		Close().Err
+}
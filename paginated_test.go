@@ -0,0 +1,36 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPaginated(t *testing.T) {
+	items := func(l *ListBuilder) error {
+		for i := 0; i < 3; i++ {
+			l.AddObject().Add("id", i).Close()
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := Paginated(&buf, 2, 9, items); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"items":[{"id":0},{"id":1},{"id":2}],"page":2,"total":9}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	err := Paginated(&buf, 2, 9, items, PageItemsKey("data"), PageNumberKey("p"), PageTotalKey("count"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"data":[{"id":0},{"id":1},{"id":2}],"p":2,"count":9}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"io"
+)
+
+// A PairListBuilder writes what would otherwise be a JSON object as a list of
+// two-element [key,value] lists, in the order they're added:
+// [["a",1],["b",2]]. This is how a JavaScript Map is commonly serialized and,
+// unlike an object, allows keys that aren't strings.
+type PairListBuilder struct {
+	l   *ListBuilder
+	Err error
+}
+
+// NewPairListBuilder returns a new PairListBuilder that writes to w.
+func NewPairListBuilder(w io.Writer) *PairListBuilder {
+	return &PairListBuilder{l: NewListBuilder(w)}
+}
+
+// Add emits a single [key,value] pair to the stream.
+func (p *PairListBuilder) Add(key, value interface{}) *PairListBuilder {
+	return p.pair(key, func(l *ListBuilder) { l.Add(value) })
+}
+
+// AddObjectFunc emits a pair with a JSON object value computed from f.
+func (p *PairListBuilder) AddObjectFunc(key interface{}, f BuilderFunc) *PairListBuilder {
+	return p.pair(key, func(l *ListBuilder) { l.AddObjectFunc(f) })
+}
+
+// AddListFunc emits a pair with a JSON list value computed from f.
+func (p *PairListBuilder) AddListFunc(key interface{}, f ListBuilderFunc) *PairListBuilder {
+	return p.pair(key, func(l *ListBuilder) { l.AddListFunc(f) })
+}
+
+func (p *PairListBuilder) pair(key interface{}, value func(*ListBuilder)) *PairListBuilder {
+	if p.Err != nil {
+		return p
+	}
+	pair := p.l.AddList().Add(key)
+	value(pair)
+	if p.Err = pair.Close().Err; p.Err == nil {
+		p.Err = p.l.Err
+	}
+	return p
+}
+
+// Close finalizes this JSON list and must be called for it to be complete.
+func (p *PairListBuilder) Close() *PairListBuilder {
+	if p.Err == nil {
+		p.Err = p.l.Close().Err
+	}
+	return p
+}
@@ -0,0 +1,38 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPairListBuilder(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPairListBuilder(&buf).
+		Add("b", 1).
+		Add(2, "a").
+		AddObjectFunc([]int{3}, func(b *Builder) error {
+			b.Add("c", true)
+			return nil
+		})
+	if p.Close(); p.Err != nil {
+		t.Fatal(p.Err)
+	}
+	if got, want := buf.String(), `[["b",1],[2,"a"],[[3],{"c":true}]]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("invalid JSON <%s>", buf.String())
+	}
+
+	buf.Reset()
+	p = NewPairListBuilder(&buf).AddListFunc("a", func(*ListBuilder) error {
+		return errors.New("fail")
+	})
+	if p.Err == nil || p.Err.Error() != "fail" {
+		t.Errorf("have <%v> want <fail>", p.Err)
+	}
+}
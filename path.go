@@ -0,0 +1,82 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// pathNode is an object buffered by AddPath. A leaf holds the encoded value,
+// any other node holds its children in the order they were first added.
+type pathNode struct {
+	raw      []byte
+	keys     []string
+	children map[string]*pathNode
+}
+
+// AddPath emits value nested under the intermediate objects named by path, so
+// AddPath([]string{"a", "b"}, 1) emits "a":{"b":1}.
+//
+// Calls that share a prefix are merged into the same intermediate objects. To
+// make this possible, the paths are buffered in a tree (values are encoded
+// immediately) and are only written, after every other pair, when the builder
+// is closed. Adding the first key of a path directly with another method
+// produces a duplicate key.
+func (b *Builder) AddPath(path []string, value interface{}) *Builder {
+	if b.done() {
+		return b
+	}
+	if len(path) == 0 {
+		b.Err = errors.New("AddPath requires a non-empty path")
+		return b
+	}
+
+	var buf bytes.Buffer
+	if b.Err = b.s.encodeTo(&buf, value); b.Err != nil {
+		return b
+	}
+
+	if b.paths == nil {
+		b.paths = &pathNode{}
+	}
+	n := b.paths
+	for i, key := range path {
+		if n.raw != nil {
+			b.Err = fmt.Errorf("AddPath %q conflicts with an earlier value at %q", path, path[:i])
+			return b
+		}
+		child, ok := n.children[key]
+		if !ok {
+			child = &pathNode{}
+			if n.children == nil {
+				n.children = make(map[string]*pathNode)
+			}
+			n.children[key] = child
+			n.keys = append(n.keys, key)
+		} else if i == len(path)-1 {
+			b.Err = fmt.Errorf("AddPath %q conflicts with an earlier path", path)
+			return b
+		}
+		n = child
+	}
+	n.raw = buf.Bytes()
+	return b
+}
+
+func (b *Builder) addPaths(n *pathNode) {
+	for _, key := range n.keys {
+		child := n.children[key]
+		if child.raw != nil {
+			if b.preadd(key) == nil {
+				b.Err = b.s.writeValue(verbatim(child.raw))
+			}
+			continue
+		}
+		b.AddObjectFunc(key, func(b *Builder) error {
+			b.addPaths(child)
+			return nil
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+var addPathTests = []struct {
+	out string
+	fn  func(*Builder)
+}{
+	{`{"a":{"b":1}}`, func(j *Builder) { j.AddPath([]string{"a", "b"}, 1) }},
+	{`{"a":{"b":{"c":"d"}}}`, func(j *Builder) { j.AddPath([]string{"a", "b", "c"}, "d") }},
+	{`{"x":0,"a":{"b":{"c":1,"d":2},"e":3}}`, func(j *Builder) {
+		j.AddPath([]string{"a", "b", "c"}, 1).
+			Add("x", 0).
+			AddPath([]string{"a", "b", "d"}, 2).
+			AddPath([]string{"a", "e"}, 3)
+	}},
+}
+
+func TestAddPath(t *testing.T) {
+	for i, test := range addPathTests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		j.Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
+
+func TestAddPathConflict(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddPath([]string{"a"}, 1).AddPath([]string{"a", "b"}, 2)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+
+	j = NewBuilder(&buf).AddPath([]string{"a", "b"}, 1).AddPath([]string{"a", "b"}, 2)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+}
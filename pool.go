@@ -0,0 +1,30 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"io"
+	"sync"
+)
+
+var builderPool = sync.Pool{New: func() interface{} { return NewBuilder(nil) }}
+
+// GetBuilder returns a Builder that writes to w, like NewBuilder, but reuses
+// one that was returned to the pool with PutBuilder if possible.
+func GetBuilder(w io.Writer) *Builder {
+	return builderPool.Get().(*Builder).Reset(w)
+}
+
+// PutBuilder returns b to the pool used by GetBuilder. b must not be used
+// afterward.
+//
+// Only a closed top-level builder (including one that failed) is returned to
+// the pool; for anything else this is a no-op. The builder's reference to its
+// writer is dropped so that the writer isn't retained by the pool.
+func PutBuilder(b *Builder) {
+	if b == nil || !b.root || (b.Err == nil && !b.closed()) {
+		return
+	}
+	b.Reset(nil)
+	builderPool.Put(b)
+}
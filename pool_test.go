@@ -0,0 +1,71 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestBuilderPool(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	j := GetBuilder(&buf1).Add("a", 1).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	PutBuilder(j)
+	if j.s.w != nil {
+		t.Error("PutBuilder retained the writer")
+	}
+
+	j = GetBuilder(&buf2).Add("b", 2)
+	j.AddListFunc("l", g).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	PutBuilder(j)
+	if got, want := buf1.String(), `{"a":1}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if got, want := buf2.String(), `{"b":2,"l":[1,2,3]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// An unclosed builder isn't pooled.
+	buf1.Reset()
+	j = GetBuilder(&buf1).Add("c", 3)
+	PutBuilder(j)
+	if j.s.w != &buf1 {
+		t.Error("PutBuilder pooled an unclosed builder")
+	}
+
+	// Neither is a sub-builder, which shares its parent's stream.
+	buf1.Reset()
+	j = GetBuilder(&buf1)
+	PutBuilder(j.AddObject("x").Add("a", 1).Close())
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf1.String(), `{"x":{"a":1}}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func BenchmarkBuilderPooled(b *testing.B) {
+	b.ReportAllocs()
+	var buf bytes.Buffer
+	for i := 0; i < b.N; i++ {
+		j := GetBuilder(&buf)
+		for i := 0; i < benchLoad; i++ {
+			j.Add(strconv.Itoa(i), i)
+		}
+		j.Close()
+		if j.Err != nil {
+			b.Fatal(j.Err)
+		}
+		PutBuilder(j)
+		b.SetBytes(int64(len(buf.Bytes())))
+		buf.Reset()
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"math"
+)
+
+// WithPythonNonFinite emits infinite and NaN float64 and float32 values as the
+// bare tokens Infinity, -Infinity, and NaN, like Python's json module does by
+// default, instead of failing.
+//
+// The output is NOT valid JSON, so this is only for interop with consumers that
+// expect Python's behavior. Only values passed directly to the builder are
+// affected.
+func (b *Builder) WithPythonNonFinite() *Builder {
+	b.s.pythonNonFinite = true
+	return b
+}
+
+// WithPythonNonFinite emits infinite and NaN float64 and float32 values as the
+// bare tokens Infinity, -Infinity, and NaN, like Python's json module does by
+// default, instead of failing.
+//
+// The output is NOT valid JSON, so this is only for interop with consumers that
+// expect Python's behavior. Only values passed directly to the builder are
+// affected.
+func (b *ListBuilder) WithPythonNonFinite() *ListBuilder {
+	b.s.pythonNonFinite = true
+	return b
+}
+
+// pythonNonFinite returns the Python token for v, if it's a non-finite float,
+// or nil.
+func pythonNonFinite(v interface{}) verbatim {
+	var f float64
+	switch v := v.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	default:
+		return nil
+	}
+	switch {
+	case math.IsNaN(f):
+		return verbatim("NaN")
+	case math.IsInf(f, 1):
+		return verbatim("Infinity")
+	case math.IsInf(f, -1):
+		return verbatim("-Infinity")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPythonNonFinite(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewListBuilder(&buf).WithPythonNonFinite().
+		AddAll(math.Inf(1), math.Inf(-1), math.NaN(), float32(math.NaN()), 1.5).
+		Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `[Infinity,-Infinity,NaN,NaN,1.5]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	for i, v := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		buf.Reset()
+		if err := NewListBuilder(&buf).Add(v).Close().Err; err == nil {
+			t.Errorf("%d expected an error without WithPythonNonFinite", i)
+		}
+		if got := buf.String(); strings.Contains(got, "Infinity") || strings.Contains(got, "NaN") {
+			t.Errorf("%d have <%s> without WithPythonNonFinite", i, got)
+		}
+	}
+}
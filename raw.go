@@ -0,0 +1,274 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// WriterFunc represents the creation of a complete JSON document.
+type WriterFunc func(io.Writer) error
+
+// ObjectWriterFunc returns a WriterFunc that writes the JSON object computed
+// from f.
+func ObjectWriterFunc(f BuilderFunc) WriterFunc {
+	return func(w io.Writer) error {
+		b := NewBuilder(w)
+		if err := f(b); err != nil {
+			return err
+		}
+		return b.Close().Err
+	}
+}
+
+// ListWriterFunc returns a WriterFunc that writes the JSON list computed from
+// f.
+func ListWriterFunc(f ListBuilderFunc) WriterFunc {
+	return func(w io.Writer) error {
+		b := NewListBuilder(w)
+		if err := f(b); err != nil {
+			return err
+		}
+		return b.Close().Err
+	}
+}
+
+// RawWriterFunc returns a WriterFunc that passes raw through with WriteRaw.
+//
+// This allows an already serialized document to share code paths with ones
+// built by a Builder or ListBuilder.
+func RawWriterFunc(raw []byte) WriterFunc {
+	return func(w io.Writer) error {
+		return WriteRaw(w, raw)
+	}
+}
+
+// AddRaw emits a key and a value that is already serialized JSON, verbatim.
+//
+// The caller is responsible for raw being a single valid JSON value, unless
+// SetValidateRaw is on. It isn't re-indented by SetIndent.
+func (b *Builder) AddRaw(key string, raw []byte) *Builder {
+	if b.done() {
+		return b
+	}
+	if b.Err = b.s.checkRaw(raw); b.Err != nil {
+		return b
+	}
+	return b.addRaw(key, raw)
+}
+
+func (b *Builder) addRaw(key string, raw []byte) *Builder {
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.write(raw)
+	return b
+}
+
+// AddRaw emits a value that is already serialized JSON, verbatim, as the next
+// element.
+//
+// The caller is responsible for raw being a single valid JSON value, unless
+// SetValidateRaw is on. It isn't re-indented by SetIndent.
+func (b *ListBuilder) AddRaw(raw []byte) *ListBuilder {
+	if b.done() {
+		return b
+	}
+	if b.Err = b.s.checkRaw(raw); b.Err != nil {
+		return b
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.write(raw)
+	b.endElement()
+	return b
+}
+
+// SetValidateRaw sets whether AddRaw checks that its argument is valid JSON
+// with json.Valid, setting Err instead of writing it if not. It's off by
+// default because it costs a scan of every raw value.
+func (b *Builder) SetValidateRaw(on bool) *Builder {
+	b.s.validateRaw = on
+	return b
+}
+
+// SetValidateRaw sets whether AddRaw checks that its argument is valid JSON
+// with json.Valid, setting Err instead of writing it if not. It's off by
+// default because it costs a scan of every raw value.
+func (b *ListBuilder) SetValidateRaw(on bool) *ListBuilder {
+	b.s.validateRaw = on
+	return b
+}
+
+// AddJSON emits a key and a value that is already serialized JSON, verbatim,
+// like AddRaw, except that jsonStr is always validated. If it isn't a single
+// valid JSON value, Err is set and nothing is written. It isn't re-indented by
+// SetIndent.
+func (b *Builder) AddJSON(key, jsonStr string) *Builder {
+	if b.done() {
+		return b
+	}
+	raw := []byte(jsonStr)
+	if !json.Valid(raw) {
+		b.Err = errors.New("AddJSON given invalid JSON")
+		return b
+	}
+	return b.addRaw(key, raw)
+}
+
+// AddJSON emits a value that is already serialized JSON, verbatim, as the next
+// element, like AddRaw, except that jsonStr is always validated. If it isn't a
+// single valid JSON value, Err is set and nothing is written. It isn't
+// re-indented by SetIndent.
+func (b *ListBuilder) AddJSON(jsonStr string) *ListBuilder {
+	if b.done() {
+		return b
+	}
+	raw := []byte(jsonStr)
+	if !json.Valid(raw) {
+		b.Err = errors.New("AddJSON given invalid JSON")
+		return b
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.write(raw)
+	b.endElement()
+	return b
+}
+
+func (s *stream) checkRaw(raw []byte) error {
+	if s.validateRaw && !json.Valid(raw) {
+		return errors.New("AddRaw given invalid JSON")
+	}
+	return nil
+}
+
+// WriteRaw writes raw, which must be a complete JSON value, to w verbatim.
+//
+// Nothing is written if raw is not valid JSON.
+func WriteRaw(w io.Writer, raw []byte) error {
+	if !json.Valid(raw) {
+		return errors.New("WriteRaw given invalid JSON")
+	}
+	_, err := w.Write(raw)
+	return err
+}
+
+// MergeRaw emits each key value pair of objectJSON, which must be a JSON object,
+// as pairs of this object, in order. The values are written verbatim, without
+// being re-marshaled.
+//
+// Nothing is written if objectJSON isn't a valid JSON object. Its keys are
+// subject to the same checks (like SetDetectDuplicateKeys) as any other.
+func (b *Builder) MergeRaw(objectJSON []byte) *Builder {
+	if b.done() {
+		return b
+	}
+	keys, values, err := splitObject(objectJSON)
+	if err != nil {
+		b.Err = err
+		return b
+	}
+	for i, key := range keys {
+		b.addRaw(key, values[i])
+	}
+	return b
+}
+
+// splitObject returns the keys and raw values of the JSON object raw, in order.
+func splitObject(raw []byte) ([]string, []json.RawMessage, error) {
+	errNotObject := errors.New("MergeRaw takes a JSON object")
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, nil, errNotObject
+	}
+	var keys []string
+	var values []json.RawMessage
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, t.(string))
+		values = append(values, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, nil, errNotObject
+	}
+	return keys, values, nil
+}
+
+// AddReader emits a key and a value that is copied, verbatim, from r until EOF,
+// without reading it all into memory.
+//
+// The caller is responsible for r containing a single valid JSON value, unless
+// SetValidateRaw is on, in which case it's checked as it's copied. Note that
+// this means some of an invalid value may have been written before Err is set.
+func (b *Builder) AddReader(key string, r io.Reader) *Builder {
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.Err = b.s.copyValue(r)
+	return b
+}
+
+// AddReader emits a value that is copied, verbatim, from r until EOF as the
+// next element, without reading it all into memory.
+//
+// The caller is responsible for r containing a single valid JSON value, unless
+// SetValidateRaw is on, in which case it's checked as it's copied. Note that
+// this means some of an invalid value may have been written before Err is set.
+func (b *ListBuilder) AddReader(r io.Reader) *ListBuilder {
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.Err = b.s.copyValue(r)
+	b.endElement()
+	return b
+}
+
+func (s *stream) copyValue(r io.Reader) error {
+	if !s.validateRaw {
+		_, err := io.Copy(s, r)
+		return err
+	}
+
+	// Scan the tokens of the value as it's copied.
+	errInvalid := errors.New("AddReader given invalid JSON")
+	dec := json.NewDecoder(io.TeeReader(r, s))
+	for depth, first := 0, true; first || depth > 0; first = false {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return errInvalid
+		} else if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errInvalid
+	}
+	return nil
+}
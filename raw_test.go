@@ -0,0 +1,212 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var writerFuncTests = []struct {
+	out string
+	fn  WriterFunc
+}{
+	{`{"foo":"bar"}`, RawWriterFunc([]byte(`{"foo":"bar"}`))},
+	{`[1,{"a":[]}]`, RawWriterFunc([]byte(`[1,{"a":[]}]`))},
+	{`{"foo":"bar"}`, ObjectWriterFunc(func(b *Builder) error { b.Add("foo", "bar"); return nil })},
+	{`[1,2,3]`, ListWriterFunc(g)},
+}
+
+func TestWriterFunc(t *testing.T) {
+	for i, test := range writerFuncTests {
+		var buf bytes.Buffer
+		if err := test.fn(&buf); err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
+
+func TestWriteRawInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRaw(&buf, []byte(`{"foo":`)); err == nil {
+		t.Error("Expected error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> want nothing written", buf.String())
+	}
+}
+
+func TestAddRaw(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(*Builder)
+	}{
+		{`{"o":{"b":1,"a":2}}`, func(j *Builder) { j.AddRaw("o", []byte(`{"b":1,"a":2}`)) }},
+		{`{"l":[1,"2"],"x":3}`, func(j *Builder) { j.AddRaw("l", []byte(`[1,"2"]`)).Add("x", 3) }},
+		{`{"l":[{"b":1},[]]}`, func(j *Builder) {
+			j.AddList("l").AddRaw([]byte(`{"b":1}`)).AddRaw([]byte(`[]`)).Close()
+		}},
+		{`{"v":{"a":1}}`, func(j *Builder) { j.SetValidateRaw(true).AddRaw("v", []byte(`{"a":1}`)) }},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
+
+func TestAddRawInvalid(t *testing.T) {
+	invalid := []byte(`{"a":`)
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetValidateRaw(true).Add("x", 1).AddRaw("r", invalid)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), `{"x":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	l := NewListBuilder(&buf).SetValidateRaw(true).AddRaw(invalid)
+	if l.Err == nil {
+		t.Error("Expected error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> want nothing written", buf.String())
+	}
+
+	// Without validation, it's the caller's responsibility.
+	buf.Reset()
+	if err := NewListBuilder(&buf).AddRaw(invalid).Close().Err; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `[{"a":]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestAddJSON(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).Add("x", 1).AddJSON("cfg", ` {"a": [1, 2]} `).AddJSON("s", `"str"`)
+	j.AddListFunc("l", func(l *ListBuilder) error {
+		l.AddJSON("3.5").AddJSON("null")
+		return nil
+	}).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"x":1,"cfg": {"a": [1, 2]} ,"s":"str","l":[3.5,null]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	for _, invalid := range []string{`{"a":`, ``, `1 2`, `'a'`} {
+		buf.Reset()
+		j := NewBuilder(&buf).Add("x", 1).AddJSON("r", invalid)
+		if j.Err == nil {
+			t.Errorf("%q: Expected error", invalid)
+		}
+		if got, want := buf.String(), `{"x":1`; got != want {
+			t.Errorf("%q: have <%s> want <%s>", invalid, got, want)
+		}
+
+		buf.Reset()
+		if l := NewListBuilder(&buf).AddJSON(invalid); l.Err == nil || buf.Len() != 0 {
+			t.Errorf("%q: have <%s> <%v> want an error and nothing written", invalid, buf.String(), l.Err)
+		}
+	}
+}
+
+func TestMergeRaw(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(*Builder)
+	}{
+		{`{}`, func(j *Builder) { j.MergeRaw([]byte(`{}`)) }},
+		{`{"b":[1, 2],"a":{"c":null}}`, func(j *Builder) { j.MergeRaw([]byte(` {"b":[1, 2], "a" : {"c":null}} `)) }},
+		{`{"x":0,"b":"y","z":true}`, func(j *Builder) {
+			j.Add("x", 0).MergeRaw([]byte(`{"b":"y"}`)).Add("z", true)
+		}},
+		{`{"o":{"a":1,"b":2}}`, func(j *Builder) {
+			j.AddObject("o").Add("a", 1).MergeRaw([]byte(`{"b":2}`)).Close()
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatalf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	for _, invalid := range []string{`[1]`, `"a"`, `{"a":}`, `{"a":1`, `{"a":1}{}`, ``} {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).Add("x", 0).MergeRaw([]byte(invalid))
+		if j.Err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+		if got, want := buf.String(), `{"x":0`; got != want {
+			t.Errorf("%s: have <%s> want <%s>", invalid, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetDetectDuplicateKeys(true).Add("a", 0).MergeRaw([]byte(`{"a":1}`))
+	if j.Err == nil {
+		t.Error("Expected duplicate key error")
+	}
+}
+
+func TestAddReader(t *testing.T) {
+	var large bytes.Buffer
+	large.WriteString(`{"items":[`)
+	for i := 0; i < 100000; i++ {
+		if i > 0 {
+			large.WriteString(",")
+		}
+		large.WriteString(`{"i":`)
+		large.WriteString(strconv.Itoa(i))
+		large.WriteString(`}`)
+	}
+	large.WriteString(`]}`)
+
+	for _, validate := range []bool{false, true} {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).SetValidateRaw(validate).Add("a", 1)
+		j.AddReader("small", strings.NewReader(`{"b":[1,2]}`))
+		j.AddReader("large", bytes.NewReader(large.Bytes()))
+		j.AddList("l").AddReader(strings.NewReader(`"x"`)).AddReader(strings.NewReader(` [] `)).Close()
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		want := `{"a":1,"small":{"b":[1,2]},"large":` + large.String() + `,"l":["x", [] ]}`
+		if got := buf.String(); got != want {
+			t.Errorf("validate=%t have <%.100s...> want <%.100s...>", validate, got, want)
+		}
+	}
+
+	for _, invalid := range []string{``, `{"a":`, `{"a":1}}`, `[1] 2`, `{]`} {
+		var buf bytes.Buffer
+		if NewBuilder(&buf).SetValidateRaw(true).AddReader("r", strings.NewReader(invalid)).Err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+		if NewListBuilder(&buf).SetValidateRaw(true).AddReader(strings.NewReader(invalid)).Err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"fmt"
+)
+
+// SetRecoverPanics sets whether a panic in an AddObjectFunc or AddListFunc
+// callback is recovered and stored in Err (wrapping the panic value, if it's an
+// error) instead of propagating. It applies to the whole document.
+//
+// The output is incomplete after a recovered panic, but every builder is left
+// in a state where it can still be closed.
+func (b *Builder) SetRecoverPanics(on bool) *Builder {
+	b.s.recoverPanics = on
+	return b
+}
+
+// SetRecoverPanics sets whether a panic in an AddObjectFunc or AddListFunc
+// callback is recovered and stored in Err (wrapping the panic value, if it's an
+// error) instead of propagating. It applies to the whole document.
+//
+// The output is incomplete after a recovered panic, but every builder is left
+// in a state where it can still be closed.
+func (b *ListBuilder) SetRecoverPanics(on bool) *ListBuilder {
+	b.s.recoverPanics = on
+	return b
+}
+
+// call runs the callback f, recovering a panic if configured to.
+func (s *stream) call(f func() error) (err error) {
+	if !s.recoverPanics {
+		return f()
+	}
+	depth := s.depth
+	defer func() {
+		if r := recover(); r != nil {
+			// Builders opened by f are abandoned.
+			s.depth = depth
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("Panic in callback: %w", e)
+			} else {
+				err = fmt.Errorf("Panic in callback: %v", r)
+			}
+		}
+	}()
+	return f()
+}
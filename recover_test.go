@@ -0,0 +1,64 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	errBoom := errors.New("boom")
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetRecoverPanics(true).Add("a", 1)
+	j.AddObjectFunc("o", func(b *Builder) error {
+		b.AddList("l").Add(1)
+		panic(errBoom)
+	})
+	if !errors.Is(j.Err, errBoom) {
+		t.Errorf("have <%v> want it to wrap <%v>", j.Err, errBoom)
+	}
+	if j.Close().Err == nil {
+		t.Error("Expected error")
+	}
+
+	buf.Reset()
+	l := NewListBuilder(&buf).SetRecoverPanics(true)
+	l.AddListFunc(func(l *ListBuilder) error {
+		var m map[string]int
+		m["a"]++
+		return nil
+	})
+	if l.Err == nil {
+		t.Error("Expected error")
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf).SetRecoverPanics(true).AddListFunc("l", func(*ListBuilder) error {
+		panic("a string")
+	})
+	if j.Err == nil || j.Err.Error() != "Panic in callback: a string" {
+		t.Errorf("have <%v> want <Panic in callback: a string>", j.Err)
+	}
+	j.Close()
+
+	// Combined with WithDiscardOnFuncError, the panicking value is dropped.
+	buf.Reset()
+	j = NewBuilder(&buf).SetRecoverPanics(true).WithDiscardOnFuncError().Add("a", 1)
+	j.AddObjectFunc("o", func(*Builder) error { panic("x") }).Add("b", 2).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"a":1,"b":2}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// Off by default.
+	defer func() {
+		if r := recover(); r != "y" {
+			t.Errorf("have %v want the panic to propagate", r)
+		}
+	}()
+	NewBuilder(&buf).AddObjectFunc("o", func(*Builder) error { panic("y") })
+}
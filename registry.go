@@ -0,0 +1,46 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"reflect"
+)
+
+// A TypeEncoderFunc writes v as a JSON object, using b. It can stream nested
+// output with b's sub-builders, and fails by setting b.Err.
+type TypeEncoderFunc func(b *Builder, v interface{})
+
+// A TypeRegistry overrides how values of specific Go types are encoded.
+type TypeRegistry struct {
+	m map[reflect.Type]TypeEncoderFunc
+}
+
+// NewTypeRegistry returns an empty TypeRegistry.
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{make(map[reflect.Type]TypeEncoderFunc)}
+}
+
+// RegisterType makes values with exactly type t encode as the object written by
+// f. A later registration for the same type replaces the earlier one.
+func (r *TypeRegistry) RegisterType(t reflect.Type, f TypeEncoderFunc) *TypeRegistry {
+	r.m[t] = f
+	return r
+}
+
+// WithTypeRegistry consults r before encoding each value in the document.
+//
+// Only values passed directly to the builder are looked up, values nested
+// inside other values are encoded by the stdlib as usual.
+func (b *Builder) WithTypeRegistry(r *TypeRegistry) *Builder {
+	b.s.types = r
+	return b
+}
+
+// WithTypeRegistry consults r before encoding each value in the document.
+//
+// Only values passed directly to the builder are looked up, values nested
+// inside other values are encoded by the stdlib as usual.
+func (b *ListBuilder) WithTypeRegistry(r *TypeRegistry) *ListBuilder {
+	b.s.types = r
+	return b
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type money struct {
+	Dollars, Cents int64
+}
+
+func TestTypeRegistry(t *testing.T) {
+	r := NewTypeRegistry().
+		RegisterType(reflect.TypeOf(money{}), func(b *Builder, v interface{}) {
+			m := v.(money)
+			b.Add("cents", m.Dollars*100+m.Cents)
+		}).
+		RegisterType(reflect.TypeOf(&money{}), func(b *Builder, v interface{}) {
+			b.AddObjectFunc("pointer", func(b *Builder) error {
+				b.AddListFunc("parts", func(l *ListBuilder) error {
+					m := v.(*money)
+					l.Add(m.Dollars).Add(m.Cents)
+					return nil
+				})
+				return nil
+			})
+		})
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).WithTypeRegistry(r)
+	j.Add("price", money{12, 99}).Add("ptr", &money{1, 2}).Add("other", 7)
+	j.AddList("prices").Add(money{0, 5}).Add(money{1, 0}).Close()
+	j.Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"price":{"cents":1299},"ptr":{"pointer":{"parts":[1,2]}},"other":7,` +
+		`"prices":[{"cents":5},{"cents":100}]}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	NewListBuilder(&buf).WithTypeRegistry(r).Add(money{3, 1}).Close()
+	if got, want := buf.String(), `[{"cents":301}]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// An indented document indents the object too.
+	buf.Reset()
+	j = NewBuilder(&buf).WithTypeRegistry(r).SetIndent("", " ").Add("p", money{0, 1}).Close()
+	if got, want := buf.String(), "{\n \"p\": {\n  \"cents\": 1\n }\n}"; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// An encoder that fails leaves neither its key nor a partial object.
+	r.RegisterType(reflect.TypeOf(money{}), func(b *Builder, v interface{}) {
+		b.Add("cents", 1)
+		b.Err = errors.New("unsupported")
+	})
+	buf.Reset()
+	if j = NewBuilder(&buf).WithTypeRegistry(r).Add("a", 1).Add("b", money{}); j.Err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), `{"a":1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
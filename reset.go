@@ -0,0 +1,63 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"io"
+)
+
+// Reset makes b equivalent to NewBuilder(w), so that it can be reused for
+// another document without allocating a new one. Err and every setting are
+// cleared. Like with NewBuilder, nothing is written until the first value is
+// added or Close is called, so that settings can be made again before the
+// opening brace is written.
+//
+// For a builder from NewBufferBuilder, a nil w instead keeps writing to its
+// internal buffer, which is emptied first, so that String, Bytes, and WriteTo
+// still work.
+//
+// It must only be called on a top-level builder, never a sub-builder.
+func (b *Builder) Reset(w io.Writer) *Builder {
+	b.s.reset(w)
+	*b = Builder{s: b.s, root: true}
+	return b
+}
+
+// Reset makes b equivalent to NewListBuilder(w), so that it can be reused for
+// another document without allocating a new one. Err and every setting are
+// cleared. Like with NewListBuilder, nothing is written until the first value
+// is added or Close is called, so that settings can be made again before the
+// opening bracket is written.
+//
+// For a builder from NewBufferListBuilder, a nil w instead keeps writing to its
+// internal buffer, which is emptied first, so that String, Bytes, and WriteTo
+// still work.
+//
+// It must only be called on a top-level builder, never a sub-builder.
+func (b *ListBuilder) Reset(w io.Writer) *ListBuilder {
+	b.s.reset(w)
+	*b = ListBuilder{s: b.s}
+	return b
+}
+
+// reset returns s to the state of newStream(w) (or, for a nil w, of a stream
+// writing to its emptied internal buffer), keeping the allocations that can be
+// reused.
+func (s *stream) reset(w io.Writer) {
+	owned := s.owned
+	if w == nil && owned != nil {
+		owned.Reset()
+		w = owned
+	} else {
+		owned = nil
+	}
+	*s = stream{
+		w:       w,
+		owned:   owned,
+		scratch: Scratch{buf: s.scratch.buf[:0]},
+		buf:     s.buf[:0],
+		free:    s.free,
+		escaper: outputEscaper{buf: s.escaper.buf[:0]},
+	}
+	s.e = newEncoder(s)
+}
@@ -0,0 +1,86 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestReset(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+	j := NewBuilder(&buf1).SetIndent("", " ").Add("a", 1)
+	j.AddObject("unclosed")
+	j.Add("b", 2)
+	if j.Err == nil {
+		t.Fatal("Expected error")
+	}
+
+	j.Reset(&buf2).Add("c", 3).AddObjectFunc("d", f).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf2.String(), `{"c":3,"d":{"baz":7}}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf1.Reset()
+	j.Reset(&buf1).Add("e", 4).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf1.String(), `{"e":4}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if got, want := buf2.String(), `{"c":3,"d":{"baz":7}}`; got != want {
+		t.Errorf("earlier output changed to <%s>", got)
+	}
+
+	buf1.Reset()
+	l := NewListBuilder(&buf2).Close()
+	l.Reset(&buf1).Add(1).AddListFunc(g).Close()
+	if l.Err != nil {
+		t.Fatal(l.Err)
+	}
+	if got, want := buf1.String(), `[1,[1,2,3]]`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	// A buffer builder reset with a nil writer keeps its internal buffer.
+	jb := NewBufferBuilder().Add("a", 1).Close()
+	if got, want := jb.String(), `{"a":1}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if got, want := jb.Reset(nil).Add("b", 2).Close().String(), `{"b":2}`; got != want || jb.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, jb.Err, want)
+	}
+	lb := NewBufferListBuilder().Add(1).Close()
+	if got, want := lb.Reset(nil).Add(2).Close().String(), `[2]`; got != want || lb.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, lb.Err, want)
+	}
+
+	// Given a writer, it writes there instead.
+	buf1.Reset()
+	if jb.Reset(&buf1).Add("c", 3).Close(); buf1.String() != `{"c":3}` || jb.Err != nil {
+		t.Errorf("have <%s> <%v> want <{\"c\":3}>", buf1.String(), jb.Err)
+	}
+}
+
+func BenchmarkBuilderReset(b *testing.B) {
+	b.ReportAllocs()
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	for i := 0; i < b.N; i++ {
+		j.Reset(&buf)
+		for i := 0; i < benchLoad; i++ {
+			j.Add(strconv.Itoa(i), i)
+		}
+		j.Close()
+		if j.Err != nil {
+			b.Fatal(j.Err)
+		}
+		b.SetBytes(int64(len(buf.Bytes())))
+		buf.Reset()
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"strconv"
+)
+
+var trueBytes = []byte("true")
+var falseBytes = []byte("false")
+var nullBytes = []byte("null")
+
+// Scratch is a reusable buffer for cheaply formatting JSON values, typically to
+// be passed to AddRaw.
+//
+// A Scratch is owned by the builder that returned it and its contents are only
+// valid until the next operation on that builder (or any builder sharing its
+// output).
+type Scratch struct {
+	buf        []byte
+	escapeHTML bool
+}
+
+// Scratch returns the builder's scratch buffer, emptied.
+func (b *Builder) Scratch() *Scratch {
+	return b.s.scratch.reset(b.s.html == nil)
+}
+
+// Scratch returns the builder's scratch buffer, emptied.
+func (b *ListBuilder) Scratch() *Scratch {
+	return b.s.scratch.reset(b.s.html == nil)
+}
+
+func (s *Scratch) reset(escapeHTML bool) *Scratch {
+	s.buf = s.buf[:0]
+	s.escapeHTML = escapeHTML
+	return s
+}
+
+// Bytes returns the formatted contents of the buffer.
+func (s *Scratch) Bytes() []byte {
+	return s.buf
+}
+
+// AppendInt appends i as a JSON number.
+func (s *Scratch) AppendInt(i int64) *Scratch {
+	s.buf = strconv.AppendInt(s.buf, i, 10)
+	return s
+}
+
+// AppendUint appends i as a JSON number.
+func (s *Scratch) AppendUint(i uint64) *Scratch {
+	s.buf = strconv.AppendUint(s.buf, i, 10)
+	return s
+}
+
+// AppendBool appends v as a JSON boolean.
+func (s *Scratch) AppendBool(v bool) *Scratch {
+	if v {
+		s.buf = append(s.buf, trueBytes...)
+	} else {
+		s.buf = append(s.buf, falseBytes...)
+	}
+	return s
+}
+
+// AppendString appends v as a quoted and escaped JSON string, following the
+// builder's SetEscapeHTML.
+func (s *Scratch) AppendString(v string) *Scratch {
+	s.buf = appendString(s.buf, v, s.escapeHTML)
+	return s
+}
+
+// AppendRaw appends p verbatim.
+func (s *Scratch) AppendRaw(p []byte) *Scratch {
+	s.buf = append(s.buf, p...)
+	return s
+}
@@ -0,0 +1,35 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestScratch(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	j.AddRaw("i", j.Scratch().AppendInt(-42).Bytes())
+	j.AddRaw("l", j.Scratch().AppendRaw(openBracketBytes).
+		AppendUint(7).AppendRaw(commaBytes).
+		AppendBool(true).AppendRaw(commaBytes).
+		AppendString(`a"b`).AppendRaw(closeBracketBytes).
+		Bytes())
+	j.Close()
+	if j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+	if got, want := buf.String(), `{"i":-42,"l":[7,true,"a\"b"]}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	j = NewBuilder(&buf)
+	j.AddRaw("h", j.Scratch().AppendString("<&>").Bytes())
+	j.SetEscapeHTML(false)
+	j.AddRaw("n", j.Scratch().AppendString("<&>").Bytes()).Close()
+	if got, want := buf.String(), `{"h":"\u003c\u0026\u003e","n":"<&>"}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
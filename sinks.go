@@ -0,0 +1,151 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"errors"
+	"io"
+)
+
+// sinkWriter writes to a primary writer and any number of secondary ones. A
+// secondary that fails is recorded and skipped from then on, but doesn't fail
+// the write.
+type sinkWriter struct {
+	primary   io.Writer
+	secondary []io.Writer
+	errs      []error
+}
+
+func (s *sinkWriter) Write(p []byte) (int, error) {
+	n, err := s.primary.Write(p)
+	if err != nil {
+		return n, err
+	}
+	for i, w := range s.secondary {
+		if s.errs[i] == nil {
+			_, s.errs[i] = w.Write(p)
+		}
+	}
+	return n, nil
+}
+
+// Flush flushes the primary writer, if it can be flushed.
+func (s *sinkWriter) Flush() error {
+	return flush(s.primary)
+}
+
+// WithSinks makes the builder write to primary, in place of the writer it was
+// created with, and copies the output to each of the secondary writers.
+//
+// Unlike the primary writer, an error from a secondary writer doesn't set Err.
+// Instead, that writer is skipped from then on and the error is available from
+// SecondaryErrs. It must be called before anything is added, otherwise Err is
+// set.
+func (b *Builder) WithSinks(primary io.Writer, secondary ...io.Writer) *Builder {
+	if b.Err == nil {
+		b.Err = b.s.withSinks(b.opened, primary, secondary)
+	}
+	return b
+}
+
+// SecondaryErrs returns, for each writer passed to WithSinks in order, the
+// error that writer failed with or nil.
+func (b *Builder) SecondaryErrs() []error {
+	return b.s.secondaryErrs()
+}
+
+// WithSinks makes the builder write to primary, in place of the writer it was
+// created with, and copies the output to each of the secondary writers.
+//
+// Unlike the primary writer, an error from a secondary writer doesn't set Err.
+// Instead, that writer is skipped from then on and the error is available from
+// SecondaryErrs. It must be called before anything is added, otherwise Err is
+// set.
+func (b *ListBuilder) WithSinks(primary io.Writer, secondary ...io.Writer) *ListBuilder {
+	if b.Err == nil {
+		b.Err = b.s.withSinks(b.opened, primary, secondary)
+	}
+	return b
+}
+
+// SecondaryErrs returns, for each writer passed to WithSinks in order, the
+// error that writer failed with or nil.
+func (b *ListBuilder) SecondaryErrs() []error {
+	return b.s.secondaryErrs()
+}
+
+func (s *stream) withSinks(opened bool, primary io.Writer, secondary []io.Writer) error {
+	if opened {
+		return errors.New("WithSinks called after output was written")
+	}
+	s.sinks = &sinkWriter{primary, secondary, make([]error, len(secondary))}
+	s.w = s.sinks
+	return nil
+}
+
+func (s *stream) secondaryErrs() []error {
+	if s.sinks == nil {
+		return nil
+	}
+	return s.sinks.errs
+}
+
+// teeWriter writes to every one of its writers, failing if any of them does.
+type teeWriter struct {
+	writers []io.Writer
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	for _, w := range t.writers {
+		n, err := w.Write(p)
+		if err != nil {
+			return n, err
+		}
+		if n != len(p) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return len(p), nil
+}
+
+// Flush flushes every writer that can be flushed.
+func (t *teeWriter) Flush() error {
+	for _, w := range t.writers {
+		if err := flush(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tee copies the output to w, in addition to the builder's own writer. Unlike
+// with WithSinks, an error from w sets Err, like one from the builder's own
+// writer. It must be called before anything is added, otherwise Err is set.
+func (b *Builder) Tee(w io.Writer) *Builder {
+	if b.Err == nil {
+		b.Err = b.s.tee(b.opened, w)
+	}
+	return b
+}
+
+// Tee copies the output to w, in addition to the builder's own writer. Unlike
+// with WithSinks, an error from w sets Err, like one from the builder's own
+// writer. It must be called before anything is added, otherwise Err is set.
+func (b *ListBuilder) Tee(w io.Writer) *ListBuilder {
+	if b.Err == nil {
+		b.Err = b.s.tee(b.opened, w)
+	}
+	return b
+}
+
+func (s *stream) tee(opened bool, w io.Writer) error {
+	if opened {
+		return errors.New("Tee called after output was written")
+	}
+	if t, ok := s.w.(*teeWriter); ok {
+		t.writers = append(t.writers, w)
+	} else {
+		s.w = &teeWriter{[]io.Writer{s.w, w}}
+	}
+	return nil
+}
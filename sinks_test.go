@@ -0,0 +1,66 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSinks(t *testing.T) {
+	var primary, healthy bytes.Buffer
+	failing := &failingWriter{failAt: 3}
+	j := NewBuilder(nil).WithSinks(&primary, failing, &healthy)
+	j.Add("foo", "bar").AddObjectFunc("quz", f).AddListFunc("quux", g).Close()
+	if j.Err != nil {
+		t.Errorf("Unexpected error <%s>", j.Err)
+	}
+
+	want := `{"foo":"bar","quz":{"baz":7},"quux":[1,2,3]}`
+	if got := primary.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if got := healthy.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	errs := j.SecondaryErrs()
+	if len(errs) != 2 || errs[0] == nil || errs[1] != nil {
+		t.Errorf("unexpected secondary errors %v", errs)
+	}
+	if failing.writes != 3 {
+		t.Errorf("have %d writes want 3", failing.writes)
+	}
+
+	var a, b bytes.Buffer
+	j = NewBuilder(&a).Add("a", 1).WithSinks(&a, &b).Add("b", 2).Close()
+	if j.Err == nil || b.Len() != 0 {
+		t.Errorf("expected an error and no output in the sink, got <%s> %v", b.String(), j.Err)
+	}
+	if NewListBuilder(&a).Add(1).WithSinks(&a, &b).Err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestTee(t *testing.T) {
+	var a, b, c bytes.Buffer
+	j := NewBuilder(&a).Tee(&b).Tee(&c)
+	j.Add("foo", "bar").AddObjectFunc("quz", f).AddListFunc("quux", g).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"foo":"bar","quz":{"baz":7},"quux":[1,2,3]}`
+	if a.String() != want || b.String() != want || c.String() != want {
+		t.Errorf("have <%s> <%s> <%s> want <%s>", a.String(), b.String(), c.String(), want)
+	}
+
+	a.Reset()
+	l := NewListBuilder(&a).Tee(&failingWriter{failAt: 3}).Add(1).Add(2).Add(3)
+	if l.Err == nil {
+		t.Error("Expected error")
+	}
+
+	if NewBuilder(&a).Add("a", 1).Tee(&b).Err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"strconv"
+)
+
+// AddSlice emits a single key value pair with a list value of values. The
+// output is the same as Add(key, values), but values isn't boxed and, for
+// slices of string, bool, int, int32, int64, and float64, each element is
+// formatted directly into the output as with the typed methods (like
+// AddString) instead of going through json.Marshal. Other element types are
+// added one at a time, as with ListBuilder.Add. Like AddList, the list counts
+// toward SetMaxDepth and Stats.
+//
+// It's a function, rather than a method, because methods can't have type
+// parameters.
+func AddSlice[T any](b *Builder, key string, values []T) *Builder {
+	if b.done() {
+		return b
+	}
+	if vs, ok := any(values).([]byte); ok {
+		// Like json.Marshal, bytes are base64 encoded.
+		return b.AddBytes(key, vs)
+	}
+	if values == nil {
+		return b.AddNull(key)
+	}
+	l := b.AddList(key)
+	addSlice(l, values)
+	if l.Close(); b.Err == nil {
+		b.Err = l.Err
+	}
+	return b
+}
+
+// addSliceElement emits values as the next element of b, like AddSlice.
+func addSliceElement[T any](b *ListBuilder, values []T) *ListBuilder {
+	l := b.AddList()
+	addSlice(l, values)
+	if l.Close(); b.Err == nil {
+		b.Err = l.Err
+	}
+	return b
+}
+
+func addSlice[T any](l *ListBuilder, values []T) {
+	if l.s.plainValues() {
+		switch vs := any(values).(type) {
+		case []string:
+			for _, v := range vs {
+				l.AddString(v)
+			}
+			return
+		case []bool:
+			for _, v := range vs {
+				l.AddBool(v)
+			}
+			return
+		case []int:
+			for _, v := range vs {
+				l.AddInt(v)
+			}
+			return
+		case []int32:
+			for _, v := range vs {
+				l.AddInt64(int64(v))
+			}
+			return
+		case []int64:
+			for _, v := range vs {
+				l.AddInt64(v)
+			}
+			return
+		case []float64:
+			for _, v := range vs {
+				l.AddFloat64(v)
+			}
+			return
+		}
+	}
+	for _, v := range values {
+		l.Add(v)
+	}
+}
+
+// plainValues returns whether no option that substitutes or re-encodes values
+// (which the typed methods bypass) is set.
+func (s *stream) plainValues() bool {
+	return s.types == nil && s.coercion == nil && s.custom == nil
+}
+
+// AddStringSlice emits a single key value pair with a list of strings value.
+// The output is the same as Add(key, values), but the list is escaped directly
+// into the output without going through json.Marshal. A nil values is emitted
+// as null.
+func (b *Builder) AddStringSlice(key string, values []string) *Builder {
+	if values == nil {
+		return b.AddNull(key)
+	}
+	if b.s.indent != nil {
+		return AddSlice(b, key, values)
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = appendStringSlice(b.s.buf[:0], values, b.s.html == nil)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddStringSlice emits a single list of strings value. The output is the same
+// as Add(values), but the list is escaped directly into the output without
+// going through json.Marshal. A nil values is emitted as null.
+func (b *ListBuilder) AddStringSlice(values []string) *ListBuilder {
+	if values == nil {
+		return b.AddNull()
+	}
+	if b.s.indent != nil {
+		return addSliceElement(b, values)
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = appendStringSlice(b.s.buf[:0], values, b.s.html == nil)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+func appendStringSlice(dst []byte, values []string, escapeHTML bool) []byte {
+	dst = append(dst, '[')
+	for i, v := range values {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendString(dst, v, escapeHTML)
+	}
+	return append(dst, ']')
+}
+
+// AddIntSlice emits a single key value pair with a list of integers value. The
+// output is the same as Add(key, values), but each integer is formatted
+// directly into the output without going through json.Marshal. A nil values is
+// emitted as null.
+func (b *Builder) AddIntSlice(key string, values []int) *Builder {
+	if values == nil {
+		return b.AddNull(key)
+	}
+	if b.s.indent != nil {
+		return AddSlice(b, key, values)
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = appendIntSlice(b.s.buf[:0], values)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddIntSlice emits a single list of integers value. The output is the same as
+// Add(values), but each integer is formatted directly into the output without
+// going through json.Marshal. A nil values is emitted as null.
+func (b *ListBuilder) AddIntSlice(values []int) *ListBuilder {
+	if values == nil {
+		return b.AddNull()
+	}
+	if b.s.indent != nil {
+		return addSliceElement(b, values)
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = appendIntSlice(b.s.buf[:0], values)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+func appendIntSlice(dst []byte, values []int) []byte {
+	dst = append(dst, '[')
+	for i, v := range values {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = strconv.AppendInt(dst, int64(v), 10)
+	}
+	return append(dst, ']')
+}
@@ -0,0 +1,155 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func testAddSliceParity[T any](t *testing.T, values []T) {
+	t.Helper()
+	var want, got bytes.Buffer
+	NewBuilder(&want).Add("a", 1).Add("k", values).Close()
+	j := AddSlice(NewBuilder(&got).Add("a", 1), "k", values).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("have <%s> want <%s>", got.String(), want.String())
+	}
+}
+
+func TestAddSlice(t *testing.T) {
+	testAddSliceParity(t, []int{1, -2, math.MaxInt64})
+	testAddSliceParity(t, []int32{math.MinInt32, 0})
+	testAddSliceParity(t, []int64{})
+	testAddSliceParity(t, []float64{0.1, 1e21, -0.0, 3})
+	testAddSliceParity(t, addStringTests)
+	testAddSliceParity(t, []bool{true, false})
+	testAddSliceParity(t, []byte("hi"))
+	testAddSliceParity(t, []uint8(nil))
+	testAddSliceParity(t, []string(nil))
+	testAddSliceParity(t, []point{{1, 2}, {3, 4}})
+	testAddSliceParity(t, []interface{}{1, "a", nil, []int{2}})
+
+	var buf bytes.Buffer
+	j := AddSlice(NewBuilder(&buf), "k", []float64{math.NaN()})
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+
+	// The list isn't a callback, so it doesn't count toward WithMaxFuncDepth,
+	// in AddSlice or in the indented fallbacks of the typed slice methods.
+	buf.Reset()
+	j = NewBuilder(&buf).WithMaxFuncDepth(1).SetIndent("", " ")
+	j.AddObjectFunc("o", func(b *Builder) error {
+		AddSlice(b, "s", []int{1})
+		b.AddIntSlice("i", []int{2})
+		b.AddList("l").AddStringSlice([]string{"a"}).Close()
+		return nil
+	})
+	if j.Close(); j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	var decoded map[string]map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded["o"]) != 3 {
+		t.Errorf("have <%s> %v", buf.String(), err)
+	}
+}
+
+func BenchmarkAddSlice(b *testing.B) {
+	ints := make([]int, benchLoad)
+	strs := make([]string, benchLoad)
+	for i := range ints {
+		ints[i] = i
+		strs[i] = "value " + strconv.Itoa(i)
+	}
+	run := func(name string, fn func(j *Builder)) {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				j := NewBuilder(&buf)
+				if fn(j); j.Close().Err != nil {
+					b.Fatal(j.Err)
+				}
+				b.SetBytes(int64(buf.Len()))
+				buf.Reset()
+			}
+		})
+	}
+	run("Add/int", func(j *Builder) { j.Add("k", ints) })
+	run("AddSlice/int", func(j *Builder) { AddSlice(j, "k", ints) })
+	run("AddIntSlice", func(j *Builder) { j.AddIntSlice("k", ints) })
+	run("Add/string", func(j *Builder) { j.Add("k", strs) })
+	run("AddSlice/string", func(j *Builder) { AddSlice(j, "k", strs) })
+	run("AddStringSlice", func(j *Builder) { j.AddStringSlice("k", strs) })
+}
+
+func TestAddStringSlice(t *testing.T) {
+	for i, values := range [][]string{nil, {}, {"a"}, addStringTests} {
+		for _, escapeHTML := range []bool{true, false} {
+			var want, got bytes.Buffer
+			NewBuilder(&want).SetEscapeHTML(escapeHTML).Add("a", 1).Add("k", values).Close()
+			j := NewBuilder(&got).SetEscapeHTML(escapeHTML).Add("a", 1).AddStringSlice("k", values).Close()
+			if j.Err != nil {
+				t.Fatal(j.Err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+			}
+
+			want.Reset()
+			got.Reset()
+			NewListBuilder(&want).SetEscapeHTML(escapeHTML).Add(values).Add(values).Close()
+			l := NewListBuilder(&got).SetEscapeHTML(escapeHTML).AddStringSlice(values).AddStringSlice(values).Close()
+			if l.Err != nil {
+				t.Fatal(l.Err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).SetIndent("", " ").AddStringSlice("k", []string{"a", "b"}).Close()
+	if got, want := buf.String(), "{\n \"k\": [\n  \"a\",\n  \"b\"\n ]\n}"; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+}
+
+func TestAddIntSlice(t *testing.T) {
+	for i, values := range [][]int{nil, {}, {7}, {-1, 0, 1, math.MinInt64, math.MaxInt64}} {
+		var want, got bytes.Buffer
+		NewBuilder(&want).Add("a", 1).Add("k", values).Close()
+		j := NewBuilder(&got).Add("a", 1).AddIntSlice("k", values).Close()
+		if j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+		}
+
+		want.Reset()
+		got.Reset()
+		NewListBuilder(&want).Add(values).Add(values).Close()
+		l := NewListBuilder(&got).AddIntSlice(values).AddIntSlice(values).Close()
+		if l.Err != nil {
+			t.Fatal(l.Err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	l := NewListBuilder(&buf).SetIndent("", " ").AddIntSlice([]int{1, -2}).Close()
+	if got, want := buf.String(), "[\n [\n  1,\n  -2\n ]\n]"; got != want || l.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, l.Err, want)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+var sseDataBytes = []byte("data: ")
+var sseEndBytes = []byte("\n\n")
+
+var bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// An SSEWriter writes JSON objects as Server-Sent Events data frames.
+type SSEWriter struct {
+	w io.Writer
+}
+
+// NewSSEWriter returns a new SSEWriter that writes events to w.
+func NewSSEWriter(w io.Writer) *SSEWriter {
+	return &SSEWriter{w}
+}
+
+// Event emits the JSON object computed from f as a single `data: <json>\n\n`
+// frame.
+//
+// The frame is built in a pooled buffer and written all at once, so nothing is
+// written if f fails. If the underlying writer can be flushed (like an
+// http.ResponseWriter), it is flushed after the frame is written.
+func (s *SSEWriter) Event(f BuilderFunc) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufferPool.Put(buf)
+	}()
+
+	buf.Write(sseDataBytes)
+	b := NewBuilder(buf)
+	if err := f(b); err != nil {
+		return err
+	}
+	if err := b.Close().Err; err != nil {
+		return err
+	}
+	buf.Write(sseEndBytes)
+
+	if _, err := s.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return flush(s.w)
+}
+
+// flush flushes w if it supports either of the common Flush signatures.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface {
+		Flush() error
+	}:
+		return f.Flush()
+	case interface {
+		Flush()
+	}:
+		f.Flush()
+	}
+	return nil
+}
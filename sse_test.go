@@ -0,0 +1,55 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (w *flushRecorder) Flush() {
+	w.flushes++
+}
+
+func TestSSEWriter(t *testing.T) {
+	var w flushRecorder
+	s := NewSSEWriter(&w)
+	for i := 0; i < 2; i++ {
+		err := s.Event(func(b *Builder) error {
+			b.Add("i", i).Add("s", "a b")
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "data: {\"i\":0,\"s\":\"a b\"}\n\ndata: {\"i\":1,\"s\":\"a b\"}\n\n"
+	if got := w.String(); got != want {
+		t.Errorf("have <%q> want <%q>", got, want)
+	}
+	if w.flushes != 2 {
+		t.Errorf("have %d flushes want 2", w.flushes)
+	}
+
+	frames := strings.Split(strings.TrimSuffix(w.String(), "\n\n"), "\n\n")
+	for i, frame := range frames {
+		var event struct {
+			I int    `json:"i"`
+			S string `json:"s"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(frame, "data: ")), &event); err != nil {
+			t.Fatalf("%d %s", i, err)
+		}
+		if event.I != i || event.S != "a b" {
+			t.Errorf("%d unexpected event %+v", i, event)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"errors"
+)
+
+// Stats are counters describing the document built so far.
+type Stats struct {
+	// Adds is the number of key value pairs and list elements added.
+	Adds int64
+	// SubBuilders is the number of nested objects and lists opened.
+	SubBuilders int64
+	// BytesWritten is the number of bytes written to the underlying writer.
+	BytesWritten int64
+	// MaxDepth is the deepest nesting reached, where the top-level object or
+	// list is 1.
+	MaxDepth int
+}
+
+// Stats returns the counters for the whole document, including every
+// sub-builder.
+func (b *Builder) Stats() Stats {
+	return b.s.stats
+}
+
+// Stats returns the counters for the whole document, including every
+// sub-builder.
+func (b *ListBuilder) Stats() Stats {
+	return b.s.stats
+}
+
+// BytesWritten returns the number of bytes written so far for the whole
+// document, including by every sub-builder. It's the same as
+// Stats().BytesWritten.
+func (b *Builder) BytesWritten() int64 {
+	return b.s.stats.BytesWritten
+}
+
+// BytesWritten returns the number of bytes written so far for the whole
+// document, including by every sub-builder. It's the same as
+// Stats().BytesWritten.
+func (b *ListBuilder) BytesWritten() int64 {
+	return b.s.stats.BytesWritten
+}
+
+// ErrMaxBytesExceeded is stored in Err when writing would make the document
+// larger than the limit set with SetMaxBytes.
+var ErrMaxBytesExceeded = errors.New("Max bytes exceeded")
+
+// SetMaxBytes limits the whole document to n bytes. A write that would exceed
+// it isn't made; instead Err is set to ErrMaxBytesExceeded. Note that this
+// means the output written up to that point is truncated JSON.
+func (b *Builder) SetMaxBytes(n int64) *Builder {
+	b.s.maxBytes = n
+	return b
+}
+
+// SetMaxBytes limits the whole document to n bytes. A write that would exceed
+// it isn't made; instead Err is set to ErrMaxBytesExceeded. Note that this
+// means the output written up to that point is truncated JSON.
+func (b *ListBuilder) SetMaxBytes(n int64) *ListBuilder {
+	b.s.maxBytes = n
+	return b
+}
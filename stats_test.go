@@ -0,0 +1,87 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	j.Add("foo", "bar").AddObjectFunc("waldo", h).AddAll("1", "one", "2", "two")
+	j.AddList("l").Add(1).Close()
+	j.Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+
+	// foo, waldo, corge, baz, grault, garply, 1, 2, 3, 1, 2, l, 1
+	want := Stats{Adds: 13, SubBuilders: 5, BytesWritten: int64(buf.Len()), MaxDepth: 4}
+	if got := j.Stats(); got != want {
+		t.Errorf("have %+v want %+v", got, want)
+	}
+}
+
+func TestBytesWritten(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf)
+	if n := j.BytesWritten(); n != 0 {
+		t.Errorf("have %d want 0", n)
+	}
+	j.Add("a", []int{1, 2}).AddString("s", "x\"y").AddBytes("b", []byte("hi"))
+	l := j.AddList("l").Add(1).AddFloat64(2.5)
+	l.AddObject().AddInt("i", 3).Close()
+	if n := l.BytesWritten(); n != int64(buf.Len()) {
+		t.Errorf("sub-builder have %d want %d", n, buf.Len())
+	}
+	l.Close()
+	j.AddObjectFunc("o", f).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if n := j.BytesWritten(); n != int64(buf.Len()) {
+		t.Errorf("have %d want %d", n, buf.Len())
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	build := func(j *Builder) *Builder {
+		j.Add("a", "bcd").AddList("l").Add(1).AddObjectFunc(f).Close()
+		return j.Close()
+	}
+	const full = `{"a":"bcd","l":[1,{"baz":7}]}`
+
+	var buf bytes.Buffer
+	if err := build(NewBuilder(&buf).SetMaxBytes(int64(len(full)))).Err; err != nil {
+		t.Fatalf("Unexpected error <%s>", err)
+	}
+	if got := buf.String(); got != full {
+		t.Errorf("have <%s> want <%s>", got, full)
+	}
+
+	buf.Reset()
+	w := new(countingWriter)
+	j := build(NewBuilder(io.MultiWriter(&buf, w)).SetMaxBytes(int64(len(full) - 1)))
+	if j.Err != ErrMaxBytesExceeded {
+		t.Errorf("have <%v> want <%v>", j.Err, ErrMaxBytesExceeded)
+	}
+	if got, want := buf.String(), full[:len(full)-1]; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+	if int(*w) != len(full)-1 || j.BytesWritten() != int64(len(full)-1) {
+		t.Errorf("have %d bytes written want %d", *w, len(full)-1)
+	}
+
+	// A write that doesn't fit isn't partially made.
+	buf.Reset()
+	l := NewListBuilder(&buf).SetMaxBytes(5).Add(1).Add("long")
+	if l.Err != ErrMaxBytesExceeded {
+		t.Errorf("have <%v> want <%v>", l.Err, ErrMaxBytesExceeded)
+	}
+	if l.Close(); buf.String() != `[1,` {
+		t.Errorf("have <%s> want <[1,>", buf.String())
+	}
+}
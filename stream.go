@@ -0,0 +1,254 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// stream is the output and configuration shared by a Builder or ListBuilder
+// and every sub-builder nested in it.
+type stream struct {
+	ctx      context.Context
+	w        io.Writer
+	e        encoder
+	scratch  Scratch
+	buf      []byte
+	sinks    *sinkWriter
+	observer func([]byte)
+	stats    Stats
+	maxBytes int64
+	owned    *bytes.Buffer
+	depth    int
+
+	types          *TypeRegistry
+	zeroTimeAsNull bool
+	coercion       *CoercionRules
+
+	indent      *indentation
+	html        *unescapedHTML
+	validateRaw bool
+	custom      Encoder
+
+	pythonNonFinite  bool
+	jsNumbers        bool
+	nullSentinel     verbatim
+	allowedKeys      map[string]struct{}
+	detectDuplicates bool
+
+	maxDepth                int
+	funcDepth, maxFuncDepth int
+	discardOnFuncError      bool
+	recoverPanics           bool
+	autoCloseSubBuilders    bool
+	keyFunc                 func(string) string
+	validateKeys            bool
+	rejectEmptyKeys         bool
+	trailingNewline         bool
+	canonical               bool
+	escapeNonASCII          bool
+	escapeSlash             bool
+	skipNilErrors           bool
+	bufferSubBuilders       bool
+	keyCache                map[string][]byte
+	free                    freeList
+	escaper                 outputEscaper
+}
+
+func newStream(w io.Writer) *stream {
+	s := &stream{w: w}
+	s.e = newEncoder(s)
+	return s
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	if s.escapeNonASCII || s.escapeSlash {
+		return s.writeEscaped(p)
+	}
+	return s.write(p)
+}
+
+func (s *stream) write(p []byte) (int, error) {
+	if s.maxBytes > 0 && s.stats.BytesWritten+int64(len(p)) > s.maxBytes {
+		return 0, ErrMaxBytesExceeded
+	}
+	n, err := s.w.Write(p)
+	s.stats.BytesWritten += int64(n)
+	if s.observer != nil && n > 0 {
+		s.observer(p[:n])
+	}
+	return n, err
+}
+
+func (s *stream) encode(v interface{}) error {
+	v, err := s.marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.writeValue(v)
+}
+
+// marshal normalizes v and, when possible, marshals it up front, so that a
+// value that fails to marshal can be rejected before anything (like its key) is
+// written. The result is passed to writeValue.
+func (s *stream) marshal(v interface{}) (interface{}, error) {
+	v, err := s.normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.(verbatim); ok {
+		return v, nil
+	}
+	if s.custom != nil {
+		return s.encodeCustom(v)
+	}
+	if c, ok := s.e.(configuredEncoder); ok {
+		raw, err := c.marshal(v)
+		return raw, err
+	}
+	if _, ok := s.e.(basicEncoder); ok {
+		if s.html != nil {
+			raw, err := s.html.marshal(v)
+			return verbatim(raw), err
+		}
+		raw, err := json.Marshal(v)
+		return verbatim(raw), err
+	}
+	return v, nil
+}
+
+// encodeKey writes key as a JSON string.
+func encodeKey[S string | []byte](s *stream, key S) error {
+	if s.keyCache != nil {
+		if raw, ok := s.keyCache[string(key)]; ok {
+			_, err := s.Write(raw)
+			return err
+		}
+	}
+	s.buf = appendString(s.buf[:0], key, s.html == nil)
+	if s.keyCache != nil {
+		s.keyCache[string(key)] = append([]byte(nil), s.buf...)
+	}
+	_, err := s.Write(s.buf)
+	return err
+}
+
+// writeValue writes a value returned by marshal.
+func (s *stream) writeValue(v interface{}) error {
+	if raw, ok := v.(verbatim); ok {
+		if s.indent != nil {
+			return s.indent.reindent(s, raw, s.depth)
+		}
+		_, err := s.Write(raw)
+		return err
+	}
+	return s.e.encode(v)
+}
+
+// encodeTo encodes v to w instead of the stream, but with the same settings.
+func (s *stream) encodeTo(w io.Writer, v interface{}) error {
+	v, err := s.marshal(v)
+	if err != nil {
+		return err
+	}
+	if raw, ok := v.(verbatim); ok {
+		_, err := w.Write(raw)
+		return err
+	}
+	return newEncoder(w).encode(v)
+}
+
+// verbatim is substituted by normalize for a value that should be written as
+// is, without being marshaled (or validated).
+type verbatim []byte
+
+// normalize applies the configured value substitutions to v before it is
+// encoded.
+func (s *stream) normalize(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case *Builder, Builder:
+		return nil, errors.New("A Builder can't be added as a value, use AddObject, AddObjectFunc, or AddRaw instead")
+	case *ListBuilder, ListBuilder:
+		return nil, errors.New("A ListBuilder can't be added as a value, use AddList, AddListFunc, or AddRaw instead")
+	}
+	if s.types != nil {
+		if f, ok := s.types.m[reflect.TypeOf(v)]; ok {
+			return s.encodeType(f, v)
+		}
+	}
+	if s.zeroTimeAsNull && isZeroTime(v) {
+		v = nil
+	}
+	if s.coercion != nil {
+		v = s.coercion.coerce(v)
+	}
+	if s.pythonNonFinite {
+		if raw := pythonNonFinite(v); raw != nil {
+			return raw, nil
+		}
+	}
+	if f, ok := v.(float64); ok && s.jsNumbers && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return verbatim(jsNumber(f)), nil
+	}
+	if s.nullSentinel != nil && isNil(v) {
+		return s.nullSentinel, nil
+	}
+	return v, nil
+}
+
+// encodeType runs f for v and returns the object it wrote. It's written to a
+// buffer so that an f that fails leaves nothing behind, and without
+// indentation, which writeValue adds.
+func (s *stream) encodeType(f TypeEncoderFunc, v interface{}) (interface{}, error) {
+	indent := s.indent
+	s.indent = nil
+	defer func() { s.indent = indent }()
+	var err error
+	raw := s.capture(func() {
+		err = s.objectFunc(func(b *Builder) error {
+			f(b, v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verbatim(raw), nil
+}
+
+// enterFunc is called before running a *Func callback and returns an error if
+// doing so would exceed the max callback depth.
+func (s *stream) enterFunc() error {
+	if s.maxFuncDepth > 0 && s.funcDepth >= s.maxFuncDepth {
+		return fmt.Errorf("Max callback depth of %d exceeded", s.maxFuncDepth)
+	}
+	s.funcDepth++
+	return nil
+}
+
+func (s *stream) exitFunc() {
+	s.funcDepth--
+}
+
+// enter records that a builder has been opened.
+func (s *stream) enter() {
+	if s.depth > 0 {
+		s.stats.SubBuilders++
+	}
+	s.depth++
+	if s.depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = s.depth
+	}
+}
+
+// exit records that a builder has been closed.
+func (s *stream) exit() {
+	s.depth--
+}
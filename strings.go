@@ -0,0 +1,110 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// AddString emits a single key value pair with a string value. The output is
+// the same as Add(key, value), but it's faster because the string is escaped
+// directly into the output without boxing it or going through json.Marshal.
+func (b *Builder) AddString(key, value string) *Builder {
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.s.buf = appendString(b.s.buf[:0], value, b.s.html == nil)
+	b.write(b.s.buf)
+	return b
+}
+
+// AddString emits a single string value. The output is the same as Add(value),
+// but it's faster because the string is escaped directly into the output
+// without boxing it or going through json.Marshal.
+func (b *ListBuilder) AddString(value string) *ListBuilder {
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.s.buf = appendString(b.s.buf[:0], value, b.s.html == nil)
+	b.write(b.s.buf)
+	b.endElement()
+	return b
+}
+
+// AddStringf emits a single key value pair with a string value formatted
+// according to format, like fmt.Sprintf. The result is escaped as in AddString.
+func (b *Builder) AddStringf(key, format string, args ...interface{}) *Builder {
+	if b.done() {
+		return b
+	}
+	return b.AddString(key, fmt.Sprintf(format, args...))
+}
+
+// AddStringf emits a single string value formatted according to format, like
+// fmt.Sprintf. The result is escaped as in AddString.
+func (b *ListBuilder) AddStringf(format string, args ...interface{}) *ListBuilder {
+	if b.done() {
+		return b
+	}
+	return b.AddString(fmt.Sprintf(format, args...))
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendString appends s to dst as a quoted JSON string, escaped exactly like
+// encoding/json does: invalid UTF-8 is replaced with U+FFFD, and U+2028 and
+// U+2029 are escaped so the output is also valid JavaScript.
+func appendString[S string | []byte](dst []byte, s S, escapeHTML bool) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && (!escapeHTML || (c != '<' && c != '>' && c != '&')) {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		// Converting at most utf8.UTFMax bytes doesn't allocate.
+		r, size := utf8.DecodeRuneInString(string(s[i:min(i+utf8.UTFMax, len(s))]))
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,102 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+var addStringTests = []string{
+	"",
+	"foo",
+	`a"b\c`,
+	"line\nbreak\r\ttab\b\f",
+	"\x00\x01\x1f\x7f",
+	"<a href='x'>&amp;</a>",
+	"  ",
+	"héllo, 世界 🎉",
+	"bad \xff utf8 \xc3",
+	"\xed\xa0\x80",
+}
+
+func TestAddString(t *testing.T) {
+	var all []byte
+	for c := 0; c < 256; c++ {
+		all = append(all, byte(c))
+	}
+	for i, s := range append(addStringTests, string(all)) {
+		for _, escapeHTML := range []bool{true, false} {
+			var want, got bytes.Buffer
+			NewBuilder(&want).SetEscapeHTML(escapeHTML).Add("k", s).Close()
+			j := NewBuilder(&got).SetEscapeHTML(escapeHTML).AddString("k", s).Close()
+			if j.Err != nil {
+				t.Fatal(j.Err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+			}
+
+			want.Reset()
+			got.Reset()
+			NewListBuilder(&want).SetEscapeHTML(escapeHTML).Add(1).Add(s).Close()
+			l := NewListBuilder(&got).SetEscapeHTML(escapeHTML).Add(1).AddString(s).Close()
+			if l.Err != nil {
+				t.Fatal(l.Err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("%d have <%s> want <%s>", i, got.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestAddStringf(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).Add("a", 1).AddStringf("id", "user-%04d", 7)
+	j.AddStringf("q", "%q said %s", "x", `"hi"`).AddStringf("p", "%.1f%%", 99.5)
+	j.AddListFunc("l", func(l *ListBuilder) error {
+		l.AddStringf("%d<%d", 1, 2).AddStringf("%v", []int{1}).Add(3)
+		return nil
+	}).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	want := `{"a":1,"id":"user-0007","q":"\"x\" said \"hi\"","p":"99.5%","l":["1\u003c2","[1]",3]}`
+	if got := buf.String(); got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func BenchmarkAddString(b *testing.B) {
+	values := make([]string, benchLoad)
+	for i := range values {
+		values[i] = "value " + strconv.Itoa(i)
+	}
+	for _, typed := range []bool{false, true} {
+		name := "Add"
+		if typed {
+			name = "AddString"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				l := NewListBuilder(&buf)
+				for _, v := range values {
+					if typed {
+						l.AddString(v)
+					} else {
+						l.Add(v)
+					}
+				}
+				if l.Close(); l.Err != nil {
+					b.Fatal(l.Err)
+				}
+				b.SetBytes(int64(buf.Len()))
+				buf.Reset()
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"strconv"
+	"time"
+)
+
+// WithZeroTimeAsNull makes zero time.Time and *time.Time values encode as null
+// instead of "0001-01-01T00:00:00Z". It applies to the whole document.
+//
+// Only times passed directly as values are affected, times nested inside other
+// values are encoded by the stdlib as usual.
+func (b *Builder) WithZeroTimeAsNull() *Builder {
+	b.s.zeroTimeAsNull = true
+	return b
+}
+
+// WithZeroTimeAsNull makes zero time.Time and *time.Time values encode as null
+// instead of "0001-01-01T00:00:00Z". It applies to the whole document.
+//
+// Only times passed directly as values are affected, times nested inside other
+// values are encoded by the stdlib as usual.
+func (b *ListBuilder) WithZeroTimeAsNull() *ListBuilder {
+	b.s.zeroTimeAsNull = true
+	return b
+}
+
+func isZeroTime(v interface{}) bool {
+	switch t := v.(type) {
+	case time.Time:
+		return t.IsZero()
+	case *time.Time:
+		return t != nil && t.IsZero()
+	}
+	return false
+}
+
+// AddTime emits t formatted with layout (see time.Time.Format) as a string
+// value with the given key. A zero t is formatted like any other, unless
+// WithZeroTimeAsNull is used, in which case it's emitted as null.
+func (b *Builder) AddTime(key string, t time.Time, layout string) *Builder {
+	if b.s.zeroTimeAsNull && t.IsZero() {
+		return b.AddNull(key)
+	}
+	return b.AddString(key, t.Format(layout))
+}
+
+// AddTimeRFC3339 is AddTime with the time.RFC3339 layout.
+func (b *Builder) AddTimeRFC3339(key string, t time.Time) *Builder {
+	return b.AddTime(key, t, time.RFC3339)
+}
+
+// AddTime emits t formatted with layout (see time.Time.Format) as a string
+// value as the next element. A zero t is formatted like any other, unless
+// WithZeroTimeAsNull is used, in which case it's emitted as null.
+func (b *ListBuilder) AddTime(t time.Time, layout string) *ListBuilder {
+	if b.s.zeroTimeAsNull && t.IsZero() {
+		return b.AddNull()
+	}
+	return b.AddString(t.Format(layout))
+}
+
+// AddTimeRFC3339 is AddTime with the time.RFC3339 layout.
+func (b *ListBuilder) AddTimeRFC3339(t time.Time) *ListBuilder {
+	return b.AddTime(t, time.RFC3339)
+}
+
+// AddTimePair emits t twice: as an RFC 3339 string with the key isoKey and as
+// an integer count of Unix seconds with the key unixKey. Sub-second precision
+// is dropped from both.
+func (b *Builder) AddTimePair(isoKey, unixKey string, t time.Time) *Builder {
+	return b.AddTimeRFC3339(isoKey, t).AddInt64(unixKey, t.Unix())
+}
+
+// AddDuration emits d as a string value with the given key, formatted by
+// d.String(), like "1h2m0.5s". This is usually more useful to people than what
+// Add emits, which is the integer number of nanoseconds.
+func (b *Builder) AddDuration(key string, d time.Duration) *Builder {
+	return b.AddString(key, d.String())
+}
+
+// AddDurationSeconds emits d as a number of seconds, like 1.5, with the given
+// key.
+func (b *Builder) AddDurationSeconds(key string, d time.Duration) *Builder {
+	return b.AddFloat64(key, d.Seconds())
+}
+
+// AddDuration emits d as a string value as the next element, formatted by
+// d.String(), like "1h2m0.5s". This is usually more useful to people than what
+// Add emits, which is the integer number of nanoseconds.
+func (b *ListBuilder) AddDuration(d time.Duration) *ListBuilder {
+	return b.AddString(d.String())
+}
+
+// AddDurationSeconds emits d as a number of seconds, like 1.5, as the next
+// element.
+func (b *ListBuilder) AddDurationSeconds(d time.Duration) *ListBuilder {
+	return b.AddFloat64(d.Seconds())
+}
+
+// AddISODuration emits d as an ISO-8601 duration string, like "PT1H30M" or
+// "PT0.25S". The largest unit used is hours, and a negative d is prefixed with
+// a minus sign.
+func (b *Builder) AddISODuration(key string, d time.Duration) *Builder {
+	return b.Add(key, isoDuration(d))
+}
+
+func isoDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	buf := make([]byte, 0, 24)
+	if d < 0 {
+		buf = append(buf, '-')
+		d = -d
+	}
+	buf = append(buf, 'P', 'T')
+
+	// Convert to unsigned so that -math.MinInt64 comes out right.
+	u := uint64(d)
+	if h := u / uint64(time.Hour); h > 0 {
+		buf = strconv.AppendUint(buf, h, 10)
+		buf = append(buf, 'H')
+		u -= h * uint64(time.Hour)
+	}
+	if m := u / uint64(time.Minute); m > 0 {
+		buf = strconv.AppendUint(buf, m, 10)
+		buf = append(buf, 'M')
+		u -= m * uint64(time.Minute)
+	}
+	if u > 0 {
+		buf = strconv.AppendUint(buf, u/uint64(time.Second), 10)
+		if frac := u % uint64(time.Second); frac > 0 {
+			digits := strconv.FormatUint(frac+uint64(time.Second), 10)[1:]
+			for digits[len(digits)-1] == '0' {
+				digits = digits[:len(digits)-1]
+			}
+			buf = append(buf, '.')
+			buf = append(buf, digits...)
+		}
+		buf = append(buf, 'S')
+	}
+	return string(buf)
+}
@@ -0,0 +1,149 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestZeroTimeAsNull(t *testing.T) {
+	var zero time.Time
+	nonZero := time.Date(2016, 2, 25, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		out string
+		fn  func(*Builder)
+	}{
+		{`{"t":null}`, func(j *Builder) { j.Add("t", zero) }},
+		{`{"t":null}`, func(j *Builder) { j.Add("t", &zero) }},
+		{`{"t":"2016-02-25T12:30:00Z"}`, func(j *Builder) { j.Add("t", nonZero) }},
+		{`{"t":"2016-02-25T12:30:00Z"}`, func(j *Builder) { j.Add("t", &nonZero) }},
+		{`{"i":0,"s":""}`, func(j *Builder) { j.Add("i", 0).Add("s", "") }},
+		{`{"o":{"t":null},"l":[null]}`, func(j *Builder) {
+			j.AddObject("o").Add("t", zero).Close()
+			j.AddList("l").Add(zero).Close()
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).WithZeroTimeAsNull()
+		test.fn(j)
+		j.Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	var buf bytes.Buffer
+	NewBuilder(&buf).Add("t", zero).Close()
+	if got, want := buf.String(), `{"t":"0001-01-01T00:00:00Z"}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+var isoDurationTests = []struct {
+	out string
+	d   time.Duration
+}{
+	{`"PT1H30M"`, 90 * time.Minute},
+	{`"PT0S"`, 0},
+	{`"PT1M2.345S"`, time.Minute + 2345*time.Millisecond},
+	{`"PT0.000000001S"`, time.Nanosecond},
+	{`"PT0.5S"`, 500 * time.Millisecond},
+	{`"PT36H0.1S"`, 36*time.Hour + 100*time.Millisecond},
+	{`"-PT5S"`, -5 * time.Second},
+	{`"-PT2562047H47M16.854775808S"`, math.MinInt64},
+}
+
+func TestAddISODuration(t *testing.T) {
+	for i, test := range isoDurationTests {
+		var buf bytes.Buffer
+		j := NewListBuilder(&buf)
+		j.AddObject().AddISODuration("d", test.d).Close()
+		j.Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got, want := buf.String(), `[{"d":`+test.out+`}]`; got != want {
+			t.Errorf("%d have <%s> want <%s>", i, got, want)
+		}
+	}
+}
+
+func TestAddDuration(t *testing.T) {
+	tests := []struct {
+		d            time.Duration
+		str, seconds string
+	}{
+		{0, `"0s"`, `0`},
+		{5 * time.Second, `"5s"`, `5`},
+		{250 * time.Millisecond, `"250ms"`, `0.25`},
+		{1500 * time.Nanosecond, `"1.5µs"`, `0.0000015`},
+		{time.Hour + 2*time.Minute + 500*time.Millisecond, `"1h2m0.5s"`, `3720.5`},
+		{-90 * time.Second, `"-1m30s"`, `-90`},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf).AddDuration("d", test.d).AddDurationSeconds("s", test.d)
+		j.AddListFunc("l", func(l *ListBuilder) error {
+			l.AddDuration(test.d).AddDurationSeconds(test.d)
+			return nil
+		}).Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		want := `{"d":` + test.str + `,"s":` + test.seconds + `,"l":[` + test.str + `,` + test.seconds + `]}`
+		if got := buf.String(); got != want {
+			t.Errorf("%d have <%s> want <%s>", i, got, want)
+		}
+	}
+}
+
+func TestAddTimePair(t *testing.T) {
+	instant := time.Date(2016, 2, 25, 12, 30, 0, 500, time.FixedZone("", -5*60*60))
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddTimePair("at", "at_unix", instant).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got, want := buf.String(), `{"at":"2016-02-25T12:30:00-05:00","at_unix":1456421400}`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+}
+
+func TestAddTime(t *testing.T) {
+	var zero time.Time
+	instant := time.Date(2016, 2, 25, 12, 30, 5, 0, time.UTC)
+	tokyo := time.Date(2016, 2, 25, 21, 30, 5, 0, time.FixedZone("JST", 9*60*60))
+	tests := []struct {
+		out string
+		fn  func(*Builder)
+	}{
+		{`{"t":"2016-02-25T12:30:05Z"}`, func(j *Builder) { j.AddTimeRFC3339("t", instant) }},
+		{`{"t":"2016-02-25T21:30:05+09:00"}`, func(j *Builder) { j.AddTimeRFC3339("t", tokyo) }},
+		{`{"t":"Feb 25 \"21:30\" JST"}`, func(j *Builder) { j.AddTime("t", tokyo, `Jan 2 "15:04" MST`) }},
+		{`{"t":"0001-01-01T00:00:00Z"}`, func(j *Builder) { j.AddTimeRFC3339("t", zero) }},
+		{`{"t":null,"u":"2016-02-25"}`, func(j *Builder) {
+			j.WithZeroTimeAsNull().AddTimeRFC3339("t", zero).AddTime("u", instant, "2006-01-02")
+		}},
+		{`{"l":["2016-02-25T12:30:05Z","12:30",null]}`, func(j *Builder) {
+			j.WithZeroTimeAsNull().AddList("l").AddTimeRFC3339(instant).AddTime(instant, "15:04").AddTimeRFC3339(zero).Close()
+		}},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		if j.Close(); j.Err != nil {
+			t.Fatal(j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
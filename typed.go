@@ -0,0 +1,167 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// AddBool emits a single key value pair with a boolean value, without
+// marshaling it.
+func (b *Builder) AddBool(key string, value bool) *Builder {
+	if value {
+		return b.addRaw(key, trueBytes)
+	}
+	return b.addRaw(key, falseBytes)
+}
+
+// AddBool emits a single boolean value, without marshaling it.
+func (b *ListBuilder) AddBool(value bool) *ListBuilder {
+	if b.preadd() != nil {
+		return b
+	}
+
+	if value {
+		b.write(trueBytes)
+	} else {
+		b.write(falseBytes)
+	}
+	b.endElement()
+	return b
+}
+
+// AddTriState emits a tri-state value: 1 as true, -1 as false, and 0 (unknown)
+// as null. Any other value sets Err.
+func (b *Builder) AddTriState(key string, v int) *Builder {
+	if b.done() {
+		return b
+	}
+	switch v {
+	case 1:
+		return b.AddBool(key, true)
+	case -1:
+		return b.AddBool(key, false)
+	case 0:
+		return b.AddNull(key)
+	}
+	if b.Err == nil {
+		b.Err = fmt.Errorf("AddTriState takes -1, 0, or 1 but got %d", v)
+	}
+	return b
+}
+
+// AddMoney emits an amount of money as an object value with the integer amount
+// in minor units (like cents) and the ISO 4217 currency code, like
+// {"amount":1299,"currency":"USD"}. A currency code that isn't three uppercase
+// letters sets Err.
+func (b *Builder) AddMoney(key string, minorUnits int64, currencyCode string) *Builder {
+	if b.done() {
+		return b
+	}
+	if !isCurrencyCode(currencyCode) {
+		if b.Err == nil {
+			b.Err = fmt.Errorf("Invalid currency code %q", currencyCode)
+		}
+		return b
+	}
+	return b.AddObjectFunc(key, func(b *Builder) error {
+		b.Add("amount", minorUnits).Add("currency", currencyCode)
+		return nil
+	})
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBytesURL emits data as an unpadded base64url string (as used by JWTs). A
+// nil data is emitted as null.
+func (b *Builder) AddBytesURL(key string, data []byte) *Builder {
+	if data == nil {
+		return b.AddNull(key)
+	}
+	return b.Add(key, base64.RawURLEncoding.EncodeToString(data))
+}
+
+var quoteBytes = []byte{'"'}
+
+// AddBytes emits data as a standard base64 string, exactly like Add(key, data)
+// but without an intermediate copy: the encoding is streamed to the output. A
+// nil data is emitted as null.
+func (b *Builder) AddBytes(key string, data []byte) *Builder {
+	if data == nil {
+		return b.AddNull(key)
+	}
+	if b.preadd(key) != nil {
+		return b
+	}
+
+	b.Err = b.s.writeBase64(data)
+	return b
+}
+
+// AddBytes emits data as a standard base64 string, exactly like Add(data) but
+// without an intermediate copy: the encoding is streamed to the output. A nil
+// data is emitted as null.
+func (b *ListBuilder) AddBytes(data []byte) *ListBuilder {
+	if data == nil {
+		return b.AddNull()
+	}
+	if b.preadd() != nil {
+		return b
+	}
+
+	b.Err = b.s.writeBase64(data)
+	b.endElement()
+	return b
+}
+
+func (s *stream) writeBase64(data []byte) error {
+	if _, err := s.Write(quoteBytes); err != nil {
+		return err
+	}
+	enc := base64.NewEncoder(base64.StdEncoding, s)
+	if _, err := enc.Write(data); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err := s.Write(quoteBytes)
+	return err
+}
+
+// AddValue emits a single key value pair. The output is the same as Add(key,
+// v), but when T is string, bool, int, int64, or float64, v isn't boxed and is
+// formatted directly into the output as with the typed methods (like
+// AddString) instead of going through json.Marshal. Any other T falls back to
+// Add.
+//
+// It's a function, rather than a method, because methods can't have type
+// parameters.
+func AddValue[T any](b *Builder, key string, v T) *Builder {
+	if b.s.plainValues() {
+		switch x := any(v).(type) {
+		case string:
+			return b.AddString(key, x)
+		case bool:
+			return b.AddBool(key, x)
+		case int:
+			return b.AddInt(key, x)
+		case int64:
+			return b.AddInt64(key, x)
+		case float64:
+			return b.AddFloat64(key, x)
+		}
+	}
+	return b.Add(key, v)
+}
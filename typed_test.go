@@ -0,0 +1,171 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var typedTests = []struct {
+	out string
+	fn  func(*Builder)
+}{
+	{`{"a":true,"b":false,"c":null,"d":1}`, func(j *Builder) {
+		j.AddBool("a", true).AddBool("b", false).AddNull("c").Add("d", 1)
+	}},
+	{`{"l":[1,true,null,false,"x"]}`, func(j *Builder) {
+		j.AddList("l").Add(1).AddBool(true).AddNull().AddBool(false).Add("x").Close()
+	}},
+	{`{"l":[null]}`, func(j *Builder) { j.AddList("l").AddNull().Close() }},
+
+	{`{"a":true}`, func(j *Builder) { j.AddTriState("a", 1) }},
+	{`{"a":false}`, func(j *Builder) { j.AddTriState("a", -1) }},
+	{`{"a":null,"b":true}`, func(j *Builder) { j.AddTriState("a", 0).AddTriState("b", 1) }},
+
+	{`{"b":"-_-_"}`, func(j *Builder) { j.AddBytesURL("b", []byte{0xfb, 0xff, 0xbf}) }},
+	{`{"b":"aGk"}`, func(j *Builder) { j.AddBytesURL("b", []byte("hi")) }},
+	{`{"b":""}`, func(j *Builder) { j.AddBytesURL("b", []byte{}) }},
+	{`{"b":null}`, func(j *Builder) { j.AddBytesURL("b", nil) }},
+
+	{`{"price":{"amount":1299,"currency":"USD"}}`, func(j *Builder) { j.AddMoney("price", 1299, "USD") }},
+	{`{"refund":{"amount":-5,"currency":"JPY"}}`, func(j *Builder) { j.AddMoney("refund", -5, "JPY") }},
+}
+
+func TestTyped(t *testing.T) {
+	for i, test := range typedTests {
+		var buf bytes.Buffer
+		j := NewBuilder(&buf)
+		test.fn(j)
+		j.Close()
+		if j.Err != nil {
+			t.Errorf("%d Unexpected error <%s>", i, j.Err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+}
+
+func TestAddTriStateInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewBuilder(&buf).AddTriState("a", 2)
+	if j.Err == nil {
+		t.Error("Expected error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> want nothing written", buf.String())
+	}
+}
+
+func TestAddMoneyInvalid(t *testing.T) {
+	for _, code := range []string{"usd", "US", "USDD", "U$D", ""} {
+		var buf bytes.Buffer
+		if j := NewBuilder(&buf).AddMoney("price", 1, code); j.Err == nil {
+			t.Errorf("%q Expected error", code)
+		}
+	}
+}
+
+func TestAddBytes(t *testing.T) {
+	long := make([]byte, 10000)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	for _, data := range [][]byte{nil, {}, {0xff}, {1, 2}, []byte("abc"), []byte("<hi>&"), long} {
+		want, err := json.Marshal(map[string][]byte{"b": data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := NewBuilder(&buf).AddBytes("b", data).Close().Err; err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != string(want) {
+			t.Errorf("have <%s> want <%s>", got, want)
+		}
+
+		want, err = json.Marshal([][]byte{data, data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Reset()
+		if err := NewListBuilder(&buf).AddBytes(data).AddBytes(data).Close().Err; err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != string(want) {
+			t.Errorf("have <%s> want <%s>", got, want)
+		}
+	}
+}
+
+func testAddValueParity[T any](t *testing.T, v T) {
+	t.Helper()
+	var want, got bytes.Buffer
+	NewBuilder(&want).Add("a", 1).Add("k", v).Close()
+	j := AddValue(NewBuilder(&got).Add("a", 1), "k", v).Close()
+	if j.Err != nil {
+		t.Fatal(j.Err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("have <%s> want <%s>", got.String(), want.String())
+	}
+}
+
+func TestAddValue(t *testing.T) {
+	for _, s := range addStringTests {
+		testAddValueParity(t, s)
+	}
+	testAddValueParity(t, true)
+	testAddValueParity(t, false)
+	testAddValueParity(t, -7)
+	testAddValueParity(t, int64(1)<<62)
+	testAddValueParity(t, 0.1)
+	testAddValueParity(t, 1e21)
+	testAddValueParity(t, point{1, 2})
+	testAddValueParity(t, struct {
+		A int    `json:"a"`
+		B string `json:"b,omitempty"`
+	}{A: 1})
+	testAddValueParity[*int](t, nil)
+
+	// Options that substitute values still apply.
+	var buf bytes.Buffer
+	reg := NewTypeRegistry().RegisterType(reflect.TypeOf(""), func(b *Builder, v interface{}) {
+		b.Add("len", len(v.(string)))
+	})
+	j := AddValue(NewBuilder(&buf).WithTypeRegistry(reg), "k", "abc").Close()
+	if got, want := buf.String(), `{"k":{"len":3}}`; got != want || j.Err != nil {
+		t.Errorf("have <%s> <%v> want <%s>", got, j.Err, want)
+	}
+}
+
+func BenchmarkAddValue(b *testing.B) {
+	for _, typed := range []bool{false, true} {
+		name := "Add"
+		if typed {
+			name = "AddValue"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf bytes.Buffer
+			for i := 0; i < b.N; i++ {
+				j := NewBuilder(&buf)
+				for n := 0; n < benchLoad; n++ {
+					if typed {
+						AddValue(j, "k", n+1000)
+					} else {
+						j.Add("k", n+1000)
+					}
+				}
+				if j.Close(); j.Err != nil {
+					b.Fatal(j.Err)
+				}
+				b.SetBytes(int64(buf.Len()))
+				buf.Reset()
+			}
+		})
+	}
+}
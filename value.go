@@ -0,0 +1,101 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"errors"
+	"io"
+	"strconv"
+)
+
+// A ValueBuilder writes a single top-level JSON value of any kind, like 42,
+// "hi", or true, not just an object or list.
+//
+// Exactly one of the Set methods may be called. The first error encountered is
+// stored in Err, after which every method is a no-op.
+type ValueBuilder struct {
+	s   *stream
+	set bool
+	Err error
+}
+
+// NewValueBuilder returns a new ValueBuilder that writes to w.
+func NewValueBuilder(w io.Writer) *ValueBuilder {
+	return &ValueBuilder{s: newStream(w)}
+}
+
+func (v *ValueBuilder) preset() error {
+	if v.Err == nil && v.set {
+		v.Err = errors.New("ValueBuilder value already set")
+	}
+	v.set = true
+	return v.Err
+}
+
+// Set emits value, marshaled like Builder.Add does.
+func (v *ValueBuilder) Set(value interface{}) error {
+	if v.Err != nil {
+		return v.Err
+	}
+	m, err := v.s.marshal(value)
+	if err != nil {
+		v.Err = err
+		return v.Err
+	}
+	if v.preset() != nil {
+		return v.Err
+	}
+	v.Err = v.s.writeValue(m)
+	return v.Err
+}
+
+// SetString emits value as a string, like Builder.AddString does.
+func (v *ValueBuilder) SetString(value string) error {
+	if v.preset() != nil {
+		return v.Err
+	}
+	v.s.buf = appendString(v.s.buf[:0], value, v.s.html == nil)
+	_, v.Err = v.s.Write(v.s.buf)
+	return v.Err
+}
+
+// SetInt emits value as an integer, like Builder.AddInt does.
+func (v *ValueBuilder) SetInt(value int) error {
+	if v.preset() != nil {
+		return v.Err
+	}
+	v.s.buf = strconv.AppendInt(v.s.buf[:0], int64(value), 10)
+	_, v.Err = v.s.Write(v.s.buf)
+	return v.Err
+}
+
+// SetBool emits value as a boolean, like Builder.AddBool does.
+func (v *ValueBuilder) SetBool(value bool) error {
+	if v.preset() != nil {
+		return v.Err
+	}
+	if value {
+		_, v.Err = v.s.Write(trueBytes)
+	} else {
+		_, v.Err = v.s.Write(falseBytes)
+	}
+	return v.Err
+}
+
+// SetObjectFunc emits the JSON object computed from f.
+func (v *ValueBuilder) SetObjectFunc(f BuilderFunc) error {
+	if v.preset() != nil {
+		return v.Err
+	}
+	v.Err = v.s.objectFunc(f)
+	return v.Err
+}
+
+// SetListFunc emits the JSON list computed from f.
+func (v *ValueBuilder) SetListFunc(f ListBuilderFunc) error {
+	if v.preset() != nil {
+		return v.Err
+	}
+	v.Err = v.s.listFunc(f)
+	return v.Err
+}
@@ -0,0 +1,63 @@
+// Copyright 2016 Daniel Harrison. All Rights Reserved.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValueBuilder(t *testing.T) {
+	tests := []struct {
+		out string
+		fn  func(*ValueBuilder) error
+	}{
+		{`"hi"`, func(v *ValueBuilder) error { return v.Set("hi") }},
+		{`"a\"b"`, func(v *ValueBuilder) error { return v.SetString(`a"b`) }},
+		{`42`, func(v *ValueBuilder) error { return v.Set(42) }},
+		{`-7`, func(v *ValueBuilder) error { return v.SetInt(-7) }},
+		{`true`, func(v *ValueBuilder) error { return v.SetBool(true) }},
+		{`null`, func(v *ValueBuilder) error { return v.Set(nil) }},
+		{`{"a":1,"b":[2]}`, func(v *ValueBuilder) error {
+			return v.Set(struct {
+				A int   `json:"a"`
+				B []int `json:"b"`
+			}{1, []int{2}})
+		}},
+		{`{"baz":7}`, func(v *ValueBuilder) error { return v.SetObjectFunc(f) }},
+		{`[1,2,3]`, func(v *ValueBuilder) error { return v.SetListFunc(g) }},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		if err := test.fn(NewValueBuilder(&buf)); err != nil {
+			t.Fatalf("%d Unexpected error <%s>", i, err)
+		}
+		if got := buf.String(); got != test.out {
+			t.Errorf("%d have <%s> want <%s>", i, got, test.out)
+		}
+	}
+
+	var buf bytes.Buffer
+	v := NewValueBuilder(&buf)
+	if err := v.Set(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SetString("two"); err == nil {
+		t.Error("Expected error")
+	}
+	if err := v.Set(3); err == nil {
+		t.Error("Expected error")
+	}
+	if got, want := buf.String(), `1`; got != want {
+		t.Errorf("have <%s> want <%s>", got, want)
+	}
+
+	buf.Reset()
+	v = NewValueBuilder(&buf)
+	if err := v.Set(make(chan int)); err == nil {
+		t.Error("Expected error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("have <%s> want nothing written", buf.String())
+	}
+}